@@ -0,0 +1,142 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "errors"
+
+// Errors returned by the variants of the most commonly used accessors
+// of the solution with the E suffix (such as Prob.ObjValE()) which
+// return an error instead of panicking: ErrDeleted if the problem has
+// been deleted and ErrOutOfRange if the row or column number is out of
+// range (for which GLPK would abort the program).
+var (
+	ErrDeleted    = errors.New("problem has been deleted")
+	ErrOutOfRange = errors.New("row or column number out of range")
+)
+
+// checkE returns ErrDeleted if the problem has been deleted.
+func (p *Prob) checkE() error {
+	if p.p.p == nil {
+		return ErrDeleted
+	}
+	return nil
+}
+
+// checkRowE returns an error if the problem has been deleted or i is
+// not a valid row number.
+func (p *Prob) checkRowE(i int) error {
+	if p.p.p == nil {
+		return ErrDeleted
+	}
+	if i < 1 || i > p.NumRows() {
+		return ErrOutOfRange
+	}
+	return nil
+}
+
+// checkColE returns an error if the problem has been deleted or j is
+// not a valid column number.
+func (p *Prob) checkColE(j int) error {
+	if p.p.p == nil {
+		return ErrDeleted
+	}
+	if j < 1 || j > p.NumCols() {
+		return ErrOutOfRange
+	}
+	return nil
+}
+
+// ObjValE is ObjVal returning an error instead of panicking.
+func (p *Prob) ObjValE() (float64, error) {
+	if err := p.checkE(); err != nil {
+		return 0, err
+	}
+	return p.ObjVal(), nil
+}
+
+// StatusE is Status returning an error instead of panicking.
+func (p *Prob) StatusE() (SolStat, error) {
+	if err := p.checkE(); err != nil {
+		return 0, err
+	}
+	return p.Status(), nil
+}
+
+// RowPrimE is RowPrim returning an error instead of panicking.
+func (p *Prob) RowPrimE(i int) (float64, error) {
+	if err := p.checkRowE(i); err != nil {
+		return 0, err
+	}
+	return p.RowPrim(i), nil
+}
+
+// RowDualE is RowDual returning an error instead of panicking.
+func (p *Prob) RowDualE(i int) (float64, error) {
+	if err := p.checkRowE(i); err != nil {
+		return 0, err
+	}
+	return p.RowDual(i), nil
+}
+
+// ColPrimE is ColPrim returning an error instead of panicking.
+func (p *Prob) ColPrimE(j int) (float64, error) {
+	if err := p.checkColE(j); err != nil {
+		return 0, err
+	}
+	return p.ColPrim(j), nil
+}
+
+// ColDualE is ColDual returning an error instead of panicking.
+func (p *Prob) ColDualE(j int) (float64, error) {
+	if err := p.checkColE(j); err != nil {
+		return 0, err
+	}
+	return p.ColDual(j), nil
+}
+
+// MipStatusE is MipStatus returning an error instead of panicking.
+func (p *Prob) MipStatusE() (SolStat, error) {
+	if err := p.checkE(); err != nil {
+		return 0, err
+	}
+	return p.MipStatus(), nil
+}
+
+// MipObjValE is MipObjVal returning an error instead of panicking.
+func (p *Prob) MipObjValE() (float64, error) {
+	if err := p.checkE(); err != nil {
+		return 0, err
+	}
+	return p.MipObjVal(), nil
+}
+
+// MipColValE is MipColVal returning an error instead of panicking.
+func (p *Prob) MipColValE(j int) (float64, error) {
+	if err := p.checkColE(j); err != nil {
+		return 0, err
+	}
+	return p.MipColVal(j), nil
+}
@@ -0,0 +1,47 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "testing"
+
+func TestAccessorsE(t *testing.T) {
+	lp := PrepareTestExample(t)
+	CheckSimplexSolution(t, lp)
+	if v, err := lp.ObjValE(); err != nil || v != lp.ObjVal() {
+		t.Errorf("expected %g but got %g (%v)", lp.ObjVal(), v, err)
+	}
+	if v, err := lp.ColPrimE(1); err != nil || v != lp.ColPrim(1) {
+		t.Errorf("expected %g but got %g (%v)", lp.ColPrim(1), v, err)
+	}
+	if v, err := lp.RowDualE(1); err != nil || v != lp.RowDual(1) {
+		t.Errorf("expected %g but got %g (%v)", lp.RowDual(1), v, err)
+	}
+	if _, err := lp.ColPrimE(4); err != ErrOutOfRange {
+		t.Errorf("expected ErrOutOfRange but got %v", err)
+	}
+	if _, err := lp.RowPrimE(0); err != ErrOutOfRange {
+		t.Errorf("expected ErrOutOfRange but got %v", err)
+	}
+	lp.Delete()
+	if _, err := lp.ObjValE(); err != ErrDeleted {
+		t.Errorf("expected ErrDeleted but got %v", err)
+	}
+	if _, err := lp.MipColValE(1); err != ErrDeleted {
+		t.Errorf("expected ErrDeleted but got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+// NewAssignment creates the assignment problem for the n×n cost
+// matrix cost: assign each of n agents to exactly one of n tasks (and
+// each task to exactly one agent) minimizing the total cost, where
+// cost[i][j] is the cost of assigning agent i to task j. The variable
+// x[i][j] (1 if agent i is assigned to task j and 0 otherwise) is
+// column i*n + j + 1, rows 1..n (i+1 for agent i) require that each
+// agent is assigned once and rows n+1..2n (n+j+1 for task j) that each
+// task is assigned once.
+//
+// The columns are continuous with bounds 0 <= x[i][j] <= 1 as the
+// constraint matrix of the problem is totally unimodular so the
+// optimal basic solution found by Prob.Simplex() is integral (use
+// ColPrim(i*n + j + 1) to read it). Panics if cost is not a square
+// matrix.
+func NewAssignment(cost [][]float64) *Prob {
+	n := len(cost)
+	for _, row := range cost {
+		if len(row) != n {
+			panic("cost should be a square matrix")
+		}
+	}
+	p := New()
+	p.SetObjDir(MIN)
+	if n == 0 {
+		return p
+	}
+	p.AddRows(2 * n)
+	for i := 1; i <= 2*n; i++ {
+		p.SetRowBnds(i, FX, 1, 1)
+	}
+	ind := []int32{0, 0, 0}
+	val := []float64{0, 1, 1}
+	for i, row := range cost {
+		for j, c := range row {
+			ind[1], ind[2] = int32(i+1), int32(n+j+1)
+			p.AddColumn(c, ind, val, 0, 1, CV)
+		}
+	}
+	return p
+}
@@ -0,0 +1,51 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "testing"
+
+func TestNewAssignment(t *testing.T) {
+	cost := [][]float64{
+		{9, 2, 7},
+		{6, 4, 3},
+		{5, 8, 1},
+	}
+	lp := NewAssignment(cost)
+	defer lp.Delete()
+	if m, n := lp.NumRows(), lp.NumCols(); m != 6 || n != 9 {
+		t.Fatalf("expected 6 rows and 9 columns but got %d and %d", m, n)
+	}
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	// agent 0 -> task 1, agent 1 -> task 0, agent 2 -> task 2
+	CheckClose(t, lp.ObjVal(), 9)
+	expected := [][]float64{{0, 1, 0}, {1, 0, 0}, {0, 0, 1}}
+	for i := range expected {
+		for j := range expected[i] {
+			CheckClose(t, lp.ColPrim(i*3+j+1), expected[i][j])
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a non-square matrix")
+		}
+	}()
+	NewAssignment([][]float64{{1, 2}})
+}
@@ -0,0 +1,189 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"math"
+	"sync"
+	"unsafe"
+)
+
+// #include <glpk.h>
+// #include <stdlib.h>
+import "C"
+
+// iosState is the Go side state of a single call to Prob.Intopt()
+// which uses the branch-and-cut callback.
+type iosState struct {
+	parm        *Iocp
+	info        *C.int // key in iosStates (in C memory as passed to GLPK)
+	panicked    bool
+	panicVal    interface{}
+	last        Progress    // last progress sent to parm.progress
+	pool        [][]float64 // recorded MIP solutions (best first)
+	bestBound   float64     // best bound over active subproblems (NaN if unknown)
+	nodeCount   int         // number of subproblems generated so far
+	initialDone bool        // initial solution already provided to GLPK
+}
+
+var (
+	iosMu     sync.Mutex
+	iosStates = make(map[C.int]*iosState)
+	iosLast   C.int
+)
+
+// newIosState creates and registers a new iosState. Its info field
+// should be passed as cb_info to the GLPK callback.
+func newIosState(parm *Iocp) *iosState {
+	s := &iosState{parm: parm, last: Progress{math.NaN(), math.NaN(), math.Inf(1)}, bestBound: math.NaN()}
+	s.info = (*C.int)(C.malloc(C.sizeof_int))
+	iosMu.Lock()
+	iosLast++
+	*s.info = iosLast
+	iosStates[iosLast] = s
+	iosMu.Unlock()
+	return s
+}
+
+// release unregisters the state.
+func (s *iosState) release() {
+	iosMu.Lock()
+	delete(iosStates, *s.info)
+	iosMu.Unlock()
+	C.free(unsafe.Pointer(s.info))
+}
+
+// callback is called by GLPK during the branch-and-cut search.
+func (s *iosState) callback(t *C.glp_tree) {
+	if node := C.glp_ios_best_node(t); node != 0 {
+		s.bestBound = float64(C.glp_ios_node_bound(t, node))
+	}
+	var aCnt, nCnt, tCnt C.int
+	C.glp_ios_tree_size(t, &aCnt, &nCnt, &tCnt)
+	s.nodeCount = int(tCnt)
+	if s.parm.nodeLim > 0 && s.nodeCount > s.parm.nodeLim {
+		C.glp_ios_terminate(t)
+	}
+	if s.parm.ctx != nil && s.parm.ctx.Err() != nil {
+		C.glp_ios_terminate(t)
+	}
+	if s.parm.poolSize > 0 && C.glp_ios_reason(t) == C.GLP_IBINGO && s.parm.iocp.presolve != C.GLP_ON {
+		s.recordSolution(t)
+	}
+	if s.parm.initialSol != nil && !s.initialDone && C.glp_ios_reason(t) == C.GLP_IHEUR && s.parm.iocp.presolve != C.GLP_ON {
+		s.initialDone = true
+		x := (&Prob{&prob{p: C.glp_ios_get_prob(t)}}).roundInt(s.parm.initialSol)
+		C.glp_ios_heur_sol(t, (*C.double)(unsafe.Pointer(&x[0])))
+	}
+	if s.parm.heuristic != nil && C.glp_ios_reason(t) == C.GLP_IHEUR && s.parm.iocp.presolve != C.GLP_ON {
+		s.heuristic(t)
+	}
+	if s.parm.callback != nil {
+		tree := &Tree{t}
+		defer func() { tree.t = nil }()
+		s.parm.callback(tree)
+	}
+	if s.parm.progress != nil {
+		s.sendProgress(t)
+	}
+}
+
+// heuristic calls the heuristic set with Iocp.SetHeuristic() with the
+// solution of the LP relaxation of the current subproblem and provides
+// the solution it returns (if valid) to GLPK.
+func (s *iosState) heuristic(t *C.glp_tree) {
+	lp := C.glp_ios_get_prob(t)
+	n := int(C.glp_get_num_cols(lp))
+	relax := make([]float64, n+1)
+	relax[0] = float64(C.glp_get_obj_val(lp))
+	for j := 1; j <= n; j++ {
+		relax[j] = float64(C.glp_get_col_prim(lp, C.int(j)))
+	}
+	x, ok := s.parm.heuristic(relax)
+	// the problem object holds the current subproblem
+	if sub := (&Prob{&prob{p: lp}}); ok && sub.isFeasible(x) {
+		x = sub.roundInt(x)
+		C.glp_ios_heur_sol(t, (*C.double)(unsafe.Pointer(&x[0])))
+	}
+}
+
+// recordSolution adds the new incumbent to the solution pool dropping
+// the worst solution if the pool is full.
+func (s *iosState) recordSolution(t *C.glp_tree) {
+	s.pool = append([][]float64{mipSolution(C.glp_ios_get_prob(t))}, s.pool...)
+	if len(s.pool) > s.parm.poolSize {
+		s.pool = s.pool[:s.parm.poolSize]
+	}
+}
+
+// sendProgress sends the current progress of the search unless it is
+// the same as the last one sent (or the receiver is not ready).
+func (s *iosState) sendProgress(t *C.glp_tree) {
+	pr := Progress{math.NaN(), math.NaN(), math.Inf(1)}
+	prob := C.glp_ios_get_prob(t)
+	if st := SolStat(C.glp_mip_status(prob)); st == FEAS || st == OPT {
+		pr.Incumbent = float64(C.glp_mip_obj_val(prob))
+		pr.Gap = float64(C.glp_ios_mip_gap(t))
+	}
+	if node := C.glp_ios_best_node(t); node != 0 {
+		pr.BestBound = float64(C.glp_ios_node_bound(t, node))
+	} else {
+		pr.BestBound = pr.Incumbent
+	}
+	if same(pr.Incumbent, s.last.Incumbent) && same(pr.BestBound, s.last.BestBound) {
+		return
+	}
+	select {
+	case s.parm.progress <- pr:
+		s.last = pr
+	default:
+	}
+}
+
+// same checks whether x and y are equal treating NaNs as equal.
+func same(x, y float64) bool {
+	return x == y || (math.IsNaN(x) && math.IsNaN(y))
+}
+
+//export goIosCallback
+func goIosCallback(t *C.glp_tree, info unsafe.Pointer) {
+	iosMu.Lock()
+	s := iosStates[*(*C.int)(info)]
+	iosMu.Unlock()
+	if s == nil || s.panicked {
+		return
+	}
+	// a panic must not unwind through the C code of GLPK so it is
+	// recovered here and repeated after glp_intopt returns
+	defer func() {
+		if r := recover(); r != nil {
+			s.panicked = true
+			s.panicVal = r
+			C.glp_ios_terminate(t)
+		}
+	}()
+	s.callback(t)
+}
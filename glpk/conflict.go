@@ -0,0 +1,83 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "errors"
+
+// ErrFeasible is returned by Prob.Conflict() if the problem is
+// feasible (so there is no conflict).
+var ErrFeasible = errors.New("problem is feasible")
+
+// Conflict finds a set of rows (constraints) which together with the
+// bounds of the columns are infeasible, i.e. an irreducible infeasible
+// subsystem (IIS) of the rows, which helps to diagnose why the problem
+// is infeasible. Integrality of the columns is ignored, i.e. the
+// conflict is found for the LP relaxation.
+//
+// It uses the deletion filter: each row in turn is removed (made
+// free) from a copy of the problem which is then checked for
+// feasibility with FindFeasible. If the copy stays infeasible the row
+// is left out, otherwise it is restored and belongs to the conflict.
+// The result is irreducible (removing any of the returned rows makes
+// the rest feasible) but not necessarily the smallest such set. It
+// requires one simplex solve per row (each warm started from the
+// previous basis), so it may be slow for large problems. The problem
+// itself is not modified.
+//
+// Returns the increasing list of the numbers of the rows forming the
+// conflict. Returns ErrFeasible if the LP relaxation of the problem
+// is feasible, or an error returned by Prob.Simplex().
+func (p *Prob) Conflict() ([]int, error) {
+	q := p.Copy(false)
+	defer q.Delete()
+	smcp := NewSmcp(WithMsgLev(MSG_OFF))
+	ok, err := q.FindFeasible(smcp)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, ErrFeasible
+	}
+	var rows []int
+	m := q.NumRows()
+	for i := 1; i <= m; i++ {
+		typ, lb, ub := q.RowType(i), q.RowLB(i), q.RowUB(i)
+		if typ == FR {
+			continue
+		}
+		q.SetRowBnds(i, FR, 0, 0)
+		ok, err := q.FindFeasible(smcp)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			// the row is needed for infeasibility
+			q.SetRowBnds(i, typ, lb, ub)
+			rows = append(rows, i)
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,64 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConflict(t *testing.T) {
+	// r1: x1 + x2 <= 1
+	// r2: x1 - x2 <= 10
+	// r3: x1 >= 2
+	// r4: x2 >= 0
+	// r5: x1 + 2 x2 >= -5
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(5)
+	lp.SetRowBnds(1, UP, 0, 1)
+	lp.SetRowBnds(2, UP, 0, 10)
+	lp.SetRowBnds(3, LO, 2, 0)
+	lp.SetRowBnds(4, LO, 0, 0)
+	lp.SetRowBnds(5, LO, -5, 0)
+	lp.AddCols(2)
+	lp.SetColBnds(1, FR, 0, 0)
+	lp.SetColBnds(2, FR, 0, 0)
+	ind := []int32{0, 1, 2}
+	lp.SetMatRow(1, ind, []float64{0, 1, 1})
+	lp.SetMatRow(2, ind, []float64{0, 1, -1})
+	lp.SetMatRow(3, ind, []float64{0, 1, 0})
+	lp.SetMatRow(4, ind, []float64{0, 0, 1})
+	lp.SetMatRow(5, ind, []float64{0, 1, 2})
+
+	rows, err := lp.Conflict()
+	if err != nil {
+		t.Fatalf("Conflict error: %v", err)
+	}
+	if want := []int{1, 3, 4}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("expected %v but got %v", want, rows)
+	}
+	if typ := lp.RowType(2); typ != UP {
+		t.Errorf("expected the problem to be unmodified but row 2 has type %v", typ)
+	}
+
+	lp.SetRowBnds(3, LO, 0, 0)
+	if _, err := lp.Conflict(); err != ErrFeasible {
+		t.Errorf("expected ErrFeasible but got %v", err)
+	}
+}
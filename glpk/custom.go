@@ -0,0 +1,160 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+)
+
+// CustomFormat is implemented by writers of text file formats not
+// supported by GLPK itself, see Prob.WriteCustom(). Such writers can
+// be implemented in Go on top of the accessors of the problem (such
+// as Rows, Cols, and MatRow).
+type CustomFormat interface {
+	// WriteProb writes the problem p to w.
+	WriteProb(w io.Writer, p *Prob) error
+}
+
+// WriteCustom writes the problem instance to w in the given format
+// (e.g. LPSolveFormat). It returns an error returned by the writer.
+func (p *Prob) WriteCustom(w io.Writer, format CustomFormat) error {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return format.WriteProb(w, p)
+}
+
+// LPSolveFormat is the LP file format of lp_solve (which differs from
+// the CPLEX LP format written by Prob.WriteLP()). Rows are always
+// written with labels (so that rows with one column are not taken as
+// bounds by lp_solve), unnamed rows as R1, R2, etc. and unnamed
+// columns as x1, x2, etc. Other names are written as they are, so they
+// must be valid lp_solve identifiers. Free rows are written with the
+// lower bound -1e30 (infinity in lp_solve).
+var LPSolveFormat CustomFormat = lpSolveFormat{}
+
+type lpSolveFormat struct{}
+
+func (lpSolveFormat) WriteProb(w io.Writer, p *Prob) error {
+	var buf bytes.Buffer
+	buf.WriteString("/* Objective function */\n")
+	if p.ObjDir() == MAX {
+		buf.WriteString("max: ")
+	} else {
+		buf.WriteString("min: ")
+	}
+	n := p.NumCols()
+	coefs := p.ObjCoefs()
+	ind := make([]int32, 1, n+1)
+	val := make([]float64, 1, n+1)
+	for j := 1; j <= n; j++ {
+		if coefs[j] != 0 {
+			ind = append(ind, int32(j))
+			val = append(val, coefs[j])
+		}
+	}
+	p.writeExpr(&buf, ind, val, coefs[0])
+	buf.WriteString(";\n")
+
+	rows := p.Rows()
+	if len(rows) > 0 {
+		buf.WriteString("\n/* Constraints */\n")
+	}
+	for _, r := range rows {
+		if r.Name != "" {
+			buf.WriteString(r.Name)
+		} else {
+			buf.WriteString("R" + strconv.Itoa(r.Index))
+		}
+		buf.WriteString(": ")
+		if r.Type == DB {
+			buf.WriteString(formatCoef(r.LB) + " <= ")
+		}
+		ind, val := p.MatRow(r.Index)
+		p.writeExpr(&buf, ind, val, 0)
+		switch r.Type {
+		case FR:
+			buf.WriteString(" >= -1e30")
+		case LO:
+			buf.WriteString(" >= " + formatCoef(r.LB))
+		case UP, DB:
+			buf.WriteString(" <= " + formatCoef(r.UB))
+		case FX:
+			buf.WriteString(" = " + formatCoef(r.LB))
+		}
+		buf.WriteString(";\n")
+	}
+
+	// lp_solve columns have bounds 0 <= x < +inf by default
+	var ints []string
+	header := false
+	for _, c := range p.Cols() {
+		name := c.Name
+		if name == "" {
+			name = "x" + strconv.Itoa(c.Index)
+		}
+		if c.Kind != CV {
+			ints = append(ints, name)
+		}
+		var bnds string
+		switch c.Type {
+		case FR:
+			bnds = name + " >= -1e30"
+		case LO:
+			if c.LB != 0 {
+				bnds = name + " >= " + formatCoef(c.LB)
+			}
+		case UP:
+			bnds = "-1e30 <= " + name + " <= " + formatCoef(c.UB)
+		case DB:
+			bnds = formatCoef(c.LB) + " <= " + name + " <= " + formatCoef(c.UB)
+		case FX:
+			bnds = name + " = " + formatCoef(c.LB)
+		}
+		if bnds == "" {
+			continue
+		}
+		if !header {
+			buf.WriteString("\n/* Bounds */\n")
+			header = true
+		}
+		buf.WriteString(bnds + ";\n")
+	}
+	if len(ints) > 0 {
+		buf.WriteString("\nint ")
+		for k, name := range ints {
+			if k > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(name)
+		}
+		buf.WriteString(";\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,50 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"testing"
+)
+
+const mipLPSolve = `/* Objective function */
+max: x1 + 2 x2 + 3 x3 + x4;
+
+/* Constraints */
+c1: 0 <= - x1 + x2 + x3 + 10 x4 <= 20;
+c2: 0 <= x1 - 3 x2 + x3 <= 30;
+c3: x2 - 3.5 x4 = 0;
+
+/* Bounds */
+0 <= x1 <= 40;
+2 <= x4 <= 3;
+
+int x4;
+`
+
+func TestWriteCustom(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	var buf bytes.Buffer
+	if err := lp.WriteCustom(&buf, LPSolveFormat); err != nil {
+		t.Fatalf("WriteCustom error: %v", err)
+	}
+	if s := buf.String(); s != mipLPSolve {
+		t.Errorf("expected:\n%s\nbut got:\n%s", mipLPSolve, s)
+	}
+}
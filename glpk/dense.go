@@ -0,0 +1,89 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "math"
+
+// NewDense creates a problem from dense data: n columns with objective
+// function coefficients obj[0..n-1] and bounds lb[j] <= x[j] <= ub[j],
+// and m rows rlb[i] <= sum_j A[i][j] x[j] <= rub[i] (for i=0..m-1),
+// with optimization direction dir. Note that all the slices are
+// indexed from 0 (i.e. column j+1 and row i+1 of the problem
+// correspond to index j and i). Use math.Inf(-1) and math.Inf(1) for
+// no lower or upper bound (the bounds types are chosen as in
+// AddColumn). Zero elements of A are skipped. Requires len(lb) =
+// len(ub) = len(obj) = n, len(rlb) = len(rub) = len(A) = m, and
+// len(A[i]) = n.
+func NewDense(obj []float64, A [][]float64, lb, ub, rlb, rub []float64, dir ObjDir) *Prob {
+	n, m := len(obj), len(A)
+	if len(lb) != n || len(ub) != n {
+		panic("len(lb) and len(ub) should be equal to len(obj)")
+	}
+	if len(rlb) != m || len(rub) != m {
+		panic("len(rlb) and len(rub) should be equal to len(A)")
+	}
+	spec := ProblemSpec{
+		Dir:  dir,
+		Rows: make([]RowSpec, m),
+		Cols: make([]ColSpec, n),
+		Ia:   []int32{0},
+		Ja:   []int32{0},
+		Ar:   []float64{0},
+	}
+	for j := range obj {
+		spec.Cols[j] = ColSpec{Coef: obj[j]}
+		spec.Cols[j].Type, spec.Cols[j].LB, spec.Cols[j].UB = denseBnds(lb[j], ub[j])
+	}
+	for i, row := range A {
+		if len(row) != n {
+			panic("len(A[i]) should be equal to len(obj)")
+		}
+		spec.Rows[i].Type, spec.Rows[i].LB, spec.Rows[i].UB = denseBnds(rlb[i], rub[i])
+		for j, v := range row {
+			if v != 0 {
+				spec.Ia = append(spec.Ia, int32(i+1))
+				spec.Ja = append(spec.Ja, int32(j+1))
+				spec.Ar = append(spec.Ar, v)
+			}
+		}
+	}
+	p := New()
+	p.LoadProblem(spec)
+	return p
+}
+
+// denseBnds returns the bounds type and the bounds (with infinite
+// ones replaced by zero) for lower bound lb and upper bound ub.
+func denseBnds(lb, ub float64) (BndsType, float64, float64) {
+	typ := bndsType(lb, ub)
+	if math.IsInf(lb, 0) {
+		lb = 0
+	}
+	if math.IsInf(ub, 0) {
+		ub = 0
+	}
+	return typ, lb, ub
+}
@@ -0,0 +1,55 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewDense(t *testing.T) {
+	inf := math.Inf(1)
+	lp := NewDense(
+		[]float64{10, 6, 4},
+		[][]float64{
+			{1, 1, 1},
+			{10, 4, 5},
+			{2, 2, 6},
+		},
+		[]float64{0, 0, 0}, []float64{inf, inf, inf},
+		[]float64{-inf, -inf, -inf}, []float64{100, 600, 300},
+		MAX)
+	defer lp.Delete()
+	if typ := lp.ColType(1); typ != LO {
+		t.Errorf("expected LO column but got %v", typ)
+	}
+	if typ := lp.RowType(1); typ != UP {
+		t.Errorf("expected UP row but got %v", typ)
+	}
+	if nz := lp.NumNz(); nz != 9 {
+		t.Errorf("expected 9 nonzero elements but got %d", nz)
+	}
+	CheckSimplexSolution(t, lp)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a row of wrong length")
+		}
+	}()
+	NewDense([]float64{1, 2}, [][]float64{{1}}, []float64{0, 0}, []float64{1, 1}, []float64{0}, []float64{1}, MIN)
+}
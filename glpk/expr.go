@@ -0,0 +1,157 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+)
+
+// ObjString returns the objective function as a human-readable
+// expression, e.g. "maximize Z: 10 x1 + 6 x2 + 4 x3". Unnamed columns
+// are shown as x1, x2, etc. Zero coefficients are omitted.
+func (p *Prob) ObjString() string {
+	var buf bytes.Buffer
+	if p.ObjDir() == MAX {
+		buf.WriteString("maximize ")
+	} else {
+		buf.WriteString("minimize ")
+	}
+	if name := p.ObjName(); name != "" {
+		buf.WriteString(name)
+	} else {
+		buf.WriteString("obj")
+	}
+	buf.WriteString(": ")
+	n := p.NumCols()
+	ind := make([]int32, 1, n+1)
+	val := make([]float64, 1, n+1)
+	for j := 1; j <= n; j++ {
+		if c := p.ObjCoef(j); c != 0 {
+			ind = append(ind, int32(j))
+			val = append(val, c)
+		}
+	}
+	p.writeExpr(&buf, ind, val, p.ObjCoef(0))
+	return buf.String()
+}
+
+// RowString returns the i-th row (constraint) as a human-readable
+// expression, e.g. "c1: 0 <= - x1 + x2 + 10 x4 <= 20". Unnamed rows
+// are shown as r1, r2, etc. and unnamed columns as x1, x2, etc.
+func (p *Prob) RowString(i int) string {
+	var buf bytes.Buffer
+	if name := p.RowName(i); name != "" {
+		buf.WriteString(name)
+	} else {
+		buf.WriteString("r" + strconv.Itoa(i))
+	}
+	buf.WriteString(": ")
+	typ, lb, ub := p.RowType(i), p.RowLB(i), p.RowUB(i)
+	if typ == DB {
+		buf.WriteString(formatCoef(lb) + " <= ")
+	}
+	ind, val := p.MatRow(i)
+	p.writeExpr(&buf, ind, val, 0)
+	switch typ {
+	case LO:
+		buf.WriteString(" >= " + formatCoef(lb))
+	case UP, DB:
+		buf.WriteString(" <= " + formatCoef(ub))
+	case FX:
+		buf.WriteString(" = " + formatCoef(lb))
+	}
+	return buf.String()
+}
+
+// terms sorts terms of a linear expression by column numbers.
+type terms struct {
+	ind []int32
+	val []float64
+}
+
+func (t terms) Len() int           { return len(t.ind) }
+func (t terms) Less(i, j int) bool { return t.ind[i] < t.ind[j] }
+func (t terms) Swap(i, j int) {
+	t.ind[i], t.ind[j] = t.ind[j], t.ind[i]
+	t.val[i], t.val[j] = t.val[j], t.val[i]
+}
+
+// writeExpr writes the linear expression val[1] x_ind[1] + ... +
+// val[n] x_ind[n] + c0 to buf (ind[0] and val[0] are ignored) with the
+// terms ordered by column numbers (GLPK does not keep the order in
+// which the elements of a row were set).
+func (p *Prob) writeExpr(buf *bytes.Buffer, ind []int32, val []float64, c0 float64) {
+	t := terms{append([]int32(nil), ind[1:]...), append([]float64(nil), val[1:]...)}
+	sort.Sort(t)
+	ind, val = append([]int32{0}, t.ind...), append([]float64{0}, t.val...)
+	empty := true
+	term := func(c float64, name string) {
+		switch {
+		case empty && c < 0:
+			buf.WriteString("- ")
+		case c < 0:
+			buf.WriteString(" - ")
+		case !empty:
+			buf.WriteString(" + ")
+		}
+		if c < 0 {
+			c = -c
+		}
+		if name == "" {
+			buf.WriteString(formatCoef(c))
+		} else if c == 1 {
+			buf.WriteString(name)
+		} else {
+			buf.WriteString(formatCoef(c) + " " + name)
+		}
+		empty = false
+	}
+	for k := 1; k < len(ind); k++ {
+		if val[k] == 0 {
+			continue
+		}
+		j := int(ind[k])
+		name := p.ColName(j)
+		if name == "" {
+			name = "x" + strconv.Itoa(j)
+		}
+		term(val[k], name)
+	}
+	if c0 != 0 {
+		term(c0, "")
+	}
+	if empty {
+		buf.WriteString("0")
+	}
+}
+
+// formatCoef formats a coefficient or a bound using the shortest
+// representation.
+func formatCoef(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,55 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "testing"
+
+func TestObjString(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if s, expected := lp.ObjString(), "maximize Z: 10 x0 + 6 x1 + 4 x2"; s != expected {
+		t.Errorf("expected %q but got %q", expected, s)
+	}
+	lp.SetObjDir(MIN)
+	lp.SetObjCoef(0, -5)
+	lp.SetObjCoef(2, 0)
+	if s, expected := lp.ObjString(), "minimize Z: 10 x0 + 4 x2 - 5"; s != expected {
+		t.Errorf("expected %q but got %q", expected, s)
+	}
+}
+
+func TestRowString(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	expected := []string{
+		"",
+		"c1: 0 <= - x1 + x2 + x3 + 10 x4 <= 20",
+		"c2: 0 <= x1 - 3 x2 + x3 <= 30",
+		"c3: x2 - 3.5 x4 = 0",
+	}
+	for i := 1; i <= lp.NumRows(); i++ {
+		if s := lp.RowString(i); s != expected[i] {
+			t.Errorf("expected %q but got %q", expected[i], s)
+		}
+	}
+	i := lp.AddRows(1)
+	lp.SetRowBnds(i, LO, 1.5, 0)
+	if s, expected := lp.RowString(i), "r4: 0 >= 1.5"; s != expected {
+		t.Errorf("expected %q but got %q", expected, s)
+	}
+}
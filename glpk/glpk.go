@@ -38,13 +38,127 @@
 package glpk
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 	"unsafe"
 )
 
 // #cgo LDFLAGS: -lglpk
 // #include <glpk.h>
+// #include <float.h>
 // #include <stdlib.h>
+//
+// static int intfeas1(glp_prob *P, int use_bound, int obj_bound) {
+// #if GLP_MAJOR_VERSION == 4 && GLP_MINOR_VERSION < 47
+//	return GLP_EFAIL;
+// #else
+//	return glp_intfeas1(P, use_bound, obj_bound);
+// #endif
+// }
+//
+// static int presolved_stats(glp_prob *P, int *m, int *n, int *nnz) {
+// #if GLP_MAJOR_VERSION == 4 && GLP_MINOR_VERSION < 58
+//	return GLP_EFAIL;
+// #else
+//	glp_prep *prep = glp_npp_alloc_wksp();
+//	glp_npp_load_prob(prep, P, GLP_SOL, GLP_OFF);
+//	int ret = glp_npp_preprocess1(prep, 0);
+//	if (ret == 0) {
+//		glp_prob *Q = glp_create_prob();
+//		glp_npp_build_prob(prep, Q);
+//		*m = glp_get_num_rows(Q);
+//		*n = glp_get_num_cols(Q);
+//		*nnz = glp_get_num_nz(Q);
+//		glp_delete_prob(Q);
+//	}
+//	glp_npp_free_wksp(prep);
+//	return ret;
+// #endif
+// }
+//
+// static int add_column(glp_prob *P, double obj, int len, const int ind[], const double val[], int type, double lb, double ub, int kind) {
+//	int j = glp_add_cols(P, 1);
+//	glp_set_obj_coef(P, j, obj);
+//	glp_set_mat_col(P, j, len, ind, val);
+//	glp_set_col_bnds(P, j, type, lb, ub);
+//	if (kind != GLP_CV) {
+//		glp_set_col_kind(P, j, kind);
+//	}
+//	return j;
+// }
+//
+// static void set_mat_rows(glp_prob *P, int n, const int rows[], const int off[], const int ind[], const double val[]) {
+//	int k;
+//	for (k = 0; k < n; k++) {
+//		glp_set_mat_row(P, rows[k], off[k+1] - off[k] - 1, ind + off[k], val + off[k]);
+//	}
+// }
+//
+// static void load_bnds(glp_prob *P, int rows, int n, const int type[], const double lb[], const double ub[]) {
+//	int k;
+//	for (k = 1; k <= n; k++) {
+//		if (type[k] == 0) {
+//			continue;
+//		}
+//		if (rows) {
+//			glp_set_row_bnds(P, k, type[k], lb[k], ub[k]);
+//		} else {
+//			glp_set_col_bnds(P, k, type[k], lb[k], ub[k]);
+//		}
+//	}
+// }
+//
+// static void load_cols(glp_prob *P, int n, const int kind[], const double coef[]) {
+//	int j;
+//	for (j = 1; j <= n; j++) {
+//		if (kind[j] != 0 && kind[j] != GLP_CV) {
+//			glp_set_col_kind(P, j, kind[j]);
+//		}
+//		if (coef[j] != 0) {
+//			glp_set_obj_coef(P, j, coef[j]);
+//		}
+//	}
+// }
+//
+// static void load_names(glp_prob *P, int rows, int n, const char *names, const int off[]) {
+//	int k;
+//	for (k = 1; k <= n; k++) {
+//		if (off[k] < 0) {
+//			continue;
+//		}
+//		if (rows) {
+//			glp_set_row_name(P, k, names + off[k]);
+//		} else {
+//			glp_set_col_name(P, k, names + off[k]);
+//		}
+//	}
+// }
+//
+// extern int goTermHook(void *info, char *s);
+// static void set_term_hook(int on) {
+//	glp_term_hook(on ? (int (*)(void *, const char *))goTermHook : NULL, NULL);
+// }
+//
+// extern void goIosCallback(glp_tree *T, void *info);
+//
+// static void set_ios_callback(glp_iocp *parm, void *info) {
+//	parm->cb_func = goIosCallback;
+//	parm->cb_info = info;
+// }
 import "C"
 
 // ObjDir is used to specify objective function direction
@@ -93,7 +207,15 @@ const (
 )
 
 type prob struct {
-	p *C.glp_prob
+	p         *C.glp_prob
+	gen       int           // environment generation (see envGen)
+	pool      [][]float64   // MIP solutions recorded by the last Intopt (best first)
+	bestBound float64       // best bound found by the last Intopt (NaN if none)
+	nodeCount int           // number of subproblems generated by the last Intopt
+	solveTime time.Duration // wall-clock time of the last solver call
+	solveErr  OptError      // error code returned by the last solver call
+	solveMip  bool          // the last solver call was glp_intopt
+	solved    bool          // a solver has been called
 }
 
 // Prob represens optimization problem. Use glpk.New() to create a new problem.
@@ -103,22 +225,96 @@ type Prob struct {
 
 // New creates a new optimization problem.
 func New() *Prob {
-	p := &prob{C.glp_create_prob()}
+	deleteFinalized()
+	p := &prob{p: C.glp_create_prob(), gen: currentEnvGen(), bestBound: math.NaN()}
+	runtime.SetFinalizer(p, finalizeProb)
 	return &Prob{p}
 }
 
+var (
+	envMu     sync.Mutex
+	envGen    int           // incremented by FreeEnv
+	finalized []*C.glp_prob // problems to be deleted by deleteFinalized
+
+	finalizedGraphs []*C.glp_graph // graphs to be deleted by deleteFinalized
+)
+
+// currentEnvGen returns the current generation of the GLPK
+// environment. Problems and graphs record the generation in which they
+// were created, so that they are not deleted after FreeEnv has already
+// freed their memory.
+func currentEnvGen() int {
+	envMu.Lock()
+	defer envMu.Unlock()
+	return envGen
+}
+
+// finalizeProb is called on garbage collection of a problem which was
+// not deleted with Prob.Delete(). The finalizer runs on its own
+// goroutine concurrently with the user code while GLPK is not thread
+// safe, so it does not call GLPK but queues the problem to be deleted
+// by the next call to New, NewGraph, Prob.Delete(), or Graph.Delete()
+// (see deleteFinalized).
+func finalizeProb(p *prob) {
+	if p.p != nil {
+		envMu.Lock()
+		if p.gen == envGen {
+			finalized = append(finalized, p.p)
+		}
+		envMu.Unlock()
+		p.p = nil
+	}
+}
+
+// deleteFinalized deletes the problems and graphs queued by
+// finalizeProb and finalizeGraph.
+func deleteFinalized() {
+	envMu.Lock()
+	probs, graphs := finalized, finalizedGraphs
+	finalized, finalizedGraphs = nil, nil
+	envMu.Unlock()
+	for _, p := range probs {
+		C.glp_delete_prob(p)
+	}
+	for _, g := range graphs {
+		C.glp_delete_graph(g)
+	}
+}
+
 // Delete deletes a problem.  Calling Delete on a deleted problem will
 // have no effect (It is save to do so). But calling any other method
 // on a deleted problem will panic. The problem will be deleted on
 // garbage collection but you can do this as soon as you no longer
-// need the optimization problem.
+// need the optimization problem. (Problems collected by the garbage
+// collector are actually deleted by the next call to New, NewGraph,
+// or Delete of a problem or a graph, as GLPK must not be called
+// concurrently from the garbage collector.)
 func (p *Prob) Delete() {
+	deleteFinalized()
 	if p.p.p != nil {
-		C.glp_delete_prob(p.p.p)
+		if p.p.gen == currentEnvGen() {
+			C.glp_delete_prob(p.p.p)
+		}
 		p.p.p = nil
+		runtime.SetFinalizer(p.p, nil)
 	}
 }
 
+// FreeEnv frees all the memory allocated by GLPK (including memory of
+// all the problems and graphs). It may be called e.g. before the
+// process exits so that memory checkers do not report GLPK memory as
+// leaked. Problems and graphs created before FreeEnv must not be used
+// afterwards, but it is safe to call Delete on them (which then has
+// no effect) or to leave them to the garbage collector (which then
+// does not delete them again). GLPK may be used again after FreeEnv.
+func FreeEnv() {
+	envMu.Lock()
+	finalized, finalizedGraphs = nil, nil // freed by glp_free_env
+	envGen++
+	envMu.Unlock()
+	C.glp_free_env()
+}
+
 // Erase erases the problem. After erasing the problem is empty as if
 // it were created with glpk.New().
 func (p *Prob) Erase() {
@@ -128,6 +324,35 @@ func (p *Prob) Erase() {
 	C.glp_erase_prob(p.p.p)
 }
 
+// Reset erases the problem and, if keep is true, restores its problem
+// name and objective direction afterwards (all other data is
+// removed). Reset is meant for reusing a single problem object for
+// many subsequent problems (e.g. one problem per goroutine in a
+// server) instead of creating a new one with glpk.New() each time.
+//
+// Erasing frees the rows and columns of the problem, thus subsequent
+// AddRows and AddCols allocate memory again just as for a problem
+// created with glpk.New(). What is saved is the allocation of the
+// problem object itself and of its Go wrapper.
+func (p *Prob) Reset(keep bool) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	var name string
+	var dir ObjDir
+	if keep {
+		name = p.ProbName()
+		dir = p.ObjDir()
+	}
+	C.glp_erase_prob(p.p.p)
+	if keep {
+		if name != "" {
+			p.SetProbName(name)
+		}
+		p.SetObjDir(dir)
+	}
+}
+
 // SetProbName sets (changes) the problem name.
 func (p *Prob) SetProbName(name string) {
 	if p.p.p == nil {
@@ -138,7 +363,8 @@ func (p *Prob) SetProbName(name string) {
 	C.glp_set_prob_name(p.p.p, s)
 }
 
-// SetObjName sets (changes) objective function name.
+// SetObjName sets (changes) objective function name. It is also the
+// name of the objective row (see SetObjRowName).
 func (p *Prob) SetObjName(name string) {
 	if p.p.p == nil {
 		panic("Prob method called on a deleted problem")
@@ -157,6 +383,68 @@ func (p *Prob) SetObjDir(dir ObjDir) {
 	C.glp_set_obj_dir(p.p.p, C.int(dir))
 }
 
+// Negate converts a minimization problem to an equivalent
+// maximization problem and vice versa, by flipping the optimization
+// direction (see ObjDir) and negating all objective function
+// coefficients (including the constant term). Optimal solutions stay
+// the same but the sign of the objective function value (ObjVal,
+// MipObjVal, etc.) is flipped. Calling Negate twice restores the
+// original problem.
+func (p *Prob) Negate() {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if p.ObjDir() == MAX {
+		p.SetObjDir(MIN)
+	} else {
+		p.SetObjDir(MAX)
+	}
+	n := int(C.glp_get_num_cols(p.p.p))
+	for j := 0; j <= n; j++ {
+		c := C.glp_get_obj_coef(p.p.p, C.int(j))
+		C.glp_set_obj_coef(p.p.p, C.int(j), -c)
+	}
+}
+
+// NormalizeObjective divides all objective function coefficients
+// (including the constant term) by the largest of their absolute
+// values, so that the largest one becomes 1 in absolute value, which
+// may improve numerical stability of badly scaled objectives. It
+// returns the scale factor (1 if all the coefficients are zero). The
+// optimal solution does not change but the objective function value
+// (ObjVal, MipObjVal, etc.) of the original objective is the reported
+// value multiplied by the scale factor.
+func (p *Prob) NormalizeObjective() float64 {
+	coefs := p.ObjCoefs()
+	scale := 0.0
+	for _, c := range coefs {
+		scale = math.Max(scale, math.Abs(c))
+	}
+	if scale == 0 {
+		return 1
+	}
+	for j, c := range coefs {
+		C.glp_set_obj_coef(p.p.p, C.int(j), C.double(c/scale))
+	}
+	return scale
+}
+
+// Minimizing checks whether the optimization direction is
+// minimization (i.e. ObjDir is glpk.MIN).
+func (p *Prob) Minimizing() bool {
+	return p.ObjDir() == MIN
+}
+
+// SetMinimizing sets optimization direction to minimization (if min
+// is true) or maximization (if min is false), see SetObjDir.
+func (p *Prob) SetMinimizing(min bool) {
+	if min {
+		p.SetObjDir(MIN)
+	} else {
+		p.SetObjDir(MAX)
+	}
+}
+
 // AddRows adds rows (constraints). Returns (1-based) index of the
 // first of the added rows.
 func (p *Prob) AddRows(nrs int) int {
@@ -185,6 +473,69 @@ func (p *Prob) SetRowName(i int, name string) {
 	C.glp_set_row_name(p.p.p, C.int(i), s)
 }
 
+// AddColumn adds a new column with objective coefficient obj, bounds
+// lb <= x <= ub (use math.Inf(-1) and math.Inf(1) for no lower or
+// upper bound), and kind, and sets its elements matrix[ind[i], j] =
+// val[i] for i=1..len(ind) (ind[0] and val[0] are ignored). It
+// returns the number j of the new column. It is equivalent to calling
+// AddCols(1), SetObjCoef, SetMatCol, SetColBnds, and SetColKind but
+// does one call to GLPK instead of five, which matters e.g. in column
+// generation. Requires len(ind) = len(val).
+func (p *Prob) AddColumn(obj float64, ind []int32, val []float64, lb, ub float64, kind VarType) int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(ind) != len(val) {
+		panic("len(ind) and len(val) should be equal")
+	}
+	typ := bndsType(lb, ub)
+	if math.IsInf(lb, 0) {
+		lb = 0
+	}
+	if math.IsInf(ub, 0) {
+		ub = 0
+	}
+	indH := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
+	valH := (*reflect.SliceHeader)(unsafe.Pointer(&val))
+	return int(C.add_column(p.p.p, C.double(obj), C.int(len(ind)-1), (*C.int)(unsafe.Pointer(indH.Data)), (*C.double)(unsafe.Pointer(valH.Data)), C.int(typ), C.double(lb), C.double(ub), C.int(kind)))
+}
+
+// AddColumnByName is like AddColumn but the elements of the new
+// column are given by row names: matrix[i, j] = coefs[name] where i
+// is the number of the row with the given name (found with FindRow,
+// so the name index is created if it does not exist yet). Returns an
+// error (and does not add the column) if there is no row with one of
+// the names.
+func (p *Prob) AddColumnByName(obj float64, coefs map[string]float64, lb, ub float64, kind VarType) (int, error) {
+	ind := make([]int32, 1, len(coefs)+1)
+	val := make([]float64, 1, len(coefs)+1)
+	for name, v := range coefs {
+		i := p.FindRow(name)
+		if i == 0 {
+			return 0, errors.New("row " + strconv.Quote(name) + " not found")
+		}
+		ind = append(ind, int32(i))
+		val = append(val, v)
+	}
+	return p.AddColumn(obj, ind, val, lb, ub, kind), nil
+}
+
+// bndsType returns the bounds type for lower bound lb and upper bound
+// ub where infinite values mean no bound.
+func bndsType(lb, ub float64) BndsType {
+	switch {
+	case math.IsInf(lb, -1) && math.IsInf(ub, 1):
+		return FR
+	case math.IsInf(ub, 1):
+		return LO
+	case math.IsInf(lb, -1):
+		return UP
+	case lb == ub:
+		return FX
+	}
+	return DB
+}
+
 // SetColName sets j-th column (variable) name.
 func (p *Prob) SetColName(j int, name string) {
 	if p.p.p == nil {
@@ -195,6 +546,34 @@ func (p *Prob) SetColName(j int, name string) {
 	C.glp_set_col_name(p.p.p, C.int(j), s)
 }
 
+// SetRowNames sets names of all rows: i-th row is given name
+// names[i-1]. Requires len(names) = NumRows().
+func (p *Prob) SetRowNames(names []string) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(names) != int(C.glp_get_num_rows(p.p.p)) {
+		panic("len(names) should be equal to the number of rows")
+	}
+	for i, name := range names {
+		p.SetRowName(i+1, name)
+	}
+}
+
+// SetColNames sets names of all columns: j-th column is given name
+// names[j-1]. Requires len(names) = NumCols().
+func (p *Prob) SetColNames(names []string) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(names) != int(C.glp_get_num_cols(p.p.p)) {
+		panic("len(names) should be equal to the number of columns")
+	}
+	for j, name := range names {
+		p.SetColName(j+1, name)
+	}
+}
+
 // SetColKind sets the kind of j-th column
 // as specified by the VarType parameter kind.
 func (p *Prob) SetColKind(j int, kind VarType) {
@@ -228,6 +607,28 @@ func (p *Prob) SetObjCoef(j int, coef float64) {
 	C.glp_set_obj_coef(p.p.p, C.int(j), C.double(coef))
 }
 
+// SetObjCoefs sets objective function coefficient of js[k]-th column
+// to coefs[k] for each k (column number 0 denotes the constant term
+// of the objective function, as for SetObjCoef). Requires len(js) =
+// len(coefs) and all column numbers in range 0..NumCols().
+func (p *Prob) SetObjCoefs(js []int, coefs []float64) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(js) != len(coefs) {
+		panic("len(js) and len(coefs) should be equal")
+	}
+	n := int(C.glp_get_num_cols(p.p.p))
+	for _, j := range js {
+		if j < 0 || j > n {
+			panic("column number out of range")
+		}
+	}
+	for k, j := range js {
+		C.glp_set_obj_coef(p.p.p, C.int(j), C.double(coefs[k]))
+	}
+}
+
 // SetMatRow sets (replaces) i-th row. It sets
 //
 //     matrix[i, ind[j]] = val[j]
@@ -246,6 +647,47 @@ func (p *Prob) SetMatRow(i int, ind []int32, val []float64) {
 	C.glp_set_mat_row(p.p.p, C.int(i), C.int(len(ind)-1), (*C.int)(unsafe.Pointer(indH.Data)), (*C.double)(unsafe.Pointer(valH.Data)))
 }
 
+// SetMatRows sets (replaces) rows rows[0], rows[1], ... as SetMatRow
+// does for each k with i = rows[k], ind = inds[k], and val = vals[k]
+// (so inds[k][0] and vals[k][0] are ignored), but with one call to
+// GLPK. Requires len(rows) = len(inds) = len(vals) and len(inds[k]) =
+// len(vals[k]) for each k.
+func (p *Prob) SetMatRows(rows []int, inds [][]int32, vals [][]float64) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(rows) != len(inds) || len(rows) != len(vals) {
+		panic("len(rows), len(inds), and len(vals) should be equal")
+	}
+	if len(rows) == 0 {
+		return
+	}
+	rowsC := make([]int32, len(rows))
+	off := make([]int32, len(rows)+1)
+	var ind []int32
+	var val []float64
+	for k, i := range rows {
+		if len(inds[k]) != len(vals[k]) {
+			panic("len(inds[k]) and len(vals[k]) should be equal")
+		}
+		rowsC[k] = int32(i)
+		if len(inds[k]) == 0 {
+			// an empty row (without the ignored element 0)
+			ind = append(ind, 0)
+			val = append(val, 0)
+		} else {
+			ind = append(ind, inds[k]...)
+			val = append(val, vals[k]...)
+		}
+		off[k+1] = int32(len(ind))
+	}
+	rowsH := (*reflect.SliceHeader)(unsafe.Pointer(&rowsC))
+	offH := (*reflect.SliceHeader)(unsafe.Pointer(&off))
+	indH := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
+	valH := (*reflect.SliceHeader)(unsafe.Pointer(&val))
+	C.set_mat_rows(p.p.p, C.int(len(rows)), (*C.int)(unsafe.Pointer(rowsH.Data)), (*C.int)(unsafe.Pointer(offH.Data)), (*C.int)(unsafe.Pointer(indH.Data)), (*C.double)(unsafe.Pointer(valH.Data)))
+}
+
 // SetMatCol sets (replaces) j-th column. It sets
 //
 //     matrix[ind[i], j] = val[i]
@@ -264,6 +706,25 @@ func (p *Prob) SetMatCol(j int, ind []int32, val []float64) {
 	C.glp_set_mat_col(p.p.p, C.int(j), C.int(len(ind)-1), (*C.int)(unsafe.Pointer(indH.Data)), (*C.double)(unsafe.Pointer(valH.Data)))
 }
 
+// ClearRow removes all elements of i-th row from the constraint
+// matrix. Together with SetRowBnds(i, glpk.FR, 0, 0) it deactivates
+// the constraint without deleting the row.
+func (p *Prob) ClearRow(i int) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_set_mat_row(p.p.p, C.int(i), 0, nil, nil)
+}
+
+// ClearCol removes all elements of j-th column from the constraint
+// matrix.
+func (p *Prob) ClearCol(j int) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_set_mat_col(p.p.p, C.int(j), 0, nil, nil)
+}
+
 // LoadMatrix replaces all of the constraint matrix. It sets
 //
 //     matrix[ia[i], ja[i]] = ar[i]
@@ -283,9 +744,187 @@ func (p *Prob) LoadMatrix(ia, ja []int32, ar []float64) {
 	C.glp_load_matrix(p.p.p, C.int(len(ia)-1), (*C.int)(unsafe.Pointer(iaH.Data)), (*C.int)(unsafe.Pointer(jaH.Data)), (*C.double)(unsafe.Pointer(arH.Data)))
 }
 
+// SetMatRow0 is like SetMatRow but ind and val have no ignored
+// leading element: it sets matrix[i, ind[k]] = val[k] for
+// k=0..len(ind)-1. Note that the row and column numbers themselves
+// are still numbered from 1. Requires len(ind) = len(val).
+func (p *Prob) SetMatRow0(i int, ind []int32, val []float64) {
+	if len(ind) != len(val) {
+		panic("len(ind) and len(val) should be equal")
+	}
+	p.SetMatRow(i, append([]int32{0}, ind...), append([]float64{0}, val...))
+}
+
+// SetMatCol0 is like SetMatCol but ind and val have no ignored
+// leading element: it sets matrix[ind[k], j] = val[k] for
+// k=0..len(ind)-1. Requires len(ind) = len(val).
+func (p *Prob) SetMatCol0(j int, ind []int32, val []float64) {
+	if len(ind) != len(val) {
+		panic("len(ind) and len(val) should be equal")
+	}
+	p.SetMatCol(j, append([]int32{0}, ind...), append([]float64{0}, val...))
+}
+
+// LoadMatrix0 is like LoadMatrix but ia, ja, and ar have no ignored
+// leading element: it sets matrix[ia[k], ja[k]] = ar[k] for
+// k=0..len(ia)-1. Requires len(ia) = len(ja) = len(ar).
+func (p *Prob) LoadMatrix0(ia, ja []int32, ar []float64) {
+	if len(ia) != len(ja) || len(ia) != len(ar) {
+		panic("len(ia) and len(ja) and len(ar) should be equal")
+	}
+	p.LoadMatrix(append([]int32{0}, ia...), append([]int32{0}, ja...), append([]float64{0}, ar...))
+}
+
+// RowSpec describes a row (constraint), see ProblemSpec.
+type RowSpec struct {
+	Name   string   // row name (empty means no name)
+	Type   BndsType // bounds type (zero value means a free row)
+	LB, UB float64  // lower and upper bounds (as in Prob.SetRowBnds())
+}
+
+// ColSpec describes a column (structural variable), see ProblemSpec.
+type ColSpec struct {
+	Name   string   // column name (empty means no name)
+	Kind   VarType  // column kind (zero value means glpk.CV)
+	Type   BndsType // bounds type (zero value means a column fixed at zero)
+	LB, UB float64  // lower and upper bounds (as in Prob.SetColBnds())
+	Coef   float64  // objective function coefficient
+}
+
+// ProblemSpec describes a whole optimization problem for
+// Prob.LoadProblem(). The zero values of its fields (and of the fields
+// of RowSpec and ColSpec) mean the same as for a newly created
+// problem and newly added rows and columns.
+type ProblemSpec struct {
+	Name     string    // problem name
+	ObjName  string    // objective function name
+	Dir      ObjDir    // optimization direction (zero value means glpk.MIN)
+	ObjConst float64   // constant term of the objective function
+	Rows     []RowSpec // Rows[i-1] describes i-th row
+	Cols     []ColSpec // Cols[j-1] describes j-th column
+	Ia, Ja   []int32   // constraint matrix as for Prob.LoadMatrix()
+	Ar       []float64 // (ia[0], ja[0], and ar[0] are ignored)
+}
+
+// LoadProblem replaces the problem with the one described by spec. It
+// is equivalent to erasing the problem and setting all the data with
+// AddRows, AddCols, SetRowBnds, SetColBnds, SetColKind, SetObjCoef,
+// SetRowName, SetColName, and LoadMatrix, but it does the work with a
+// small number of calls to GLPK (independent of the size of the
+// problem), which is much faster for large problems.
+func (p *Prob) LoadProblem(spec ProblemSpec) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(spec.Ia) != len(spec.Ja) || len(spec.Ia) != len(spec.Ar) {
+		panic("len(Ia) and len(Ja) and len(Ar) should be equal")
+	}
+	C.glp_erase_prob(p.p.p)
+	if spec.Name != "" {
+		p.SetProbName(spec.Name)
+	}
+	if spec.ObjName != "" {
+		p.SetObjName(spec.ObjName)
+	}
+	if spec.Dir != 0 {
+		p.SetObjDir(spec.Dir)
+	}
+	if spec.ObjConst != 0 {
+		p.SetObjCoef(0, spec.ObjConst)
+	}
+	m, n := len(spec.Rows), len(spec.Cols)
+	if m > 0 {
+		C.glp_add_rows(p.p.p, C.int(m))
+		typ := make([]int32, m+1)
+		lb := make([]float64, m+1)
+		ub := make([]float64, m+1)
+		names := make([]string, m+1)
+		for i, r := range spec.Rows {
+			typ[i+1], lb[i+1], ub[i+1] = int32(r.Type), r.LB, r.UB
+			names[i+1] = r.Name
+		}
+		p.loadBnds(true, typ, lb, ub)
+		p.loadNames(true, names)
+	}
+	if n > 0 {
+		C.glp_add_cols(p.p.p, C.int(n))
+		typ := make([]int32, n+1)
+		lb := make([]float64, n+1)
+		ub := make([]float64, n+1)
+		kind := make([]int32, n+1)
+		coef := make([]float64, n+1)
+		names := make([]string, n+1)
+		for j, c := range spec.Cols {
+			typ[j+1], lb[j+1], ub[j+1] = int32(c.Type), c.LB, c.UB
+			kind[j+1], coef[j+1] = int32(c.Kind), c.Coef
+			names[j+1] = c.Name
+		}
+		p.loadBnds(false, typ, lb, ub)
+		kindH := (*reflect.SliceHeader)(unsafe.Pointer(&kind))
+		coefH := (*reflect.SliceHeader)(unsafe.Pointer(&coef))
+		C.load_cols(p.p.p, C.int(n), (*C.int)(unsafe.Pointer(kindH.Data)), (*C.double)(unsafe.Pointer(coefH.Data)))
+		p.loadNames(false, names)
+	}
+	if len(spec.Ia) > 0 {
+		p.LoadMatrix(spec.Ia, spec.Ja, spec.Ar)
+	}
+}
+
+// loadBnds sets bounds of all rows (or columns) for which typ[k] is
+// not zero.
+func (p *Prob) loadBnds(rows bool, typ []int32, lb, ub []float64) {
+	var rowsC C.int
+	if rows {
+		rowsC = 1
+	}
+	typH := (*reflect.SliceHeader)(unsafe.Pointer(&typ))
+	lbH := (*reflect.SliceHeader)(unsafe.Pointer(&lb))
+	ubH := (*reflect.SliceHeader)(unsafe.Pointer(&ub))
+	C.load_bnds(p.p.p, rowsC, C.int(len(typ)-1), (*C.int)(unsafe.Pointer(typH.Data)), (*C.double)(unsafe.Pointer(lbH.Data)), (*C.double)(unsafe.Pointer(ubH.Data)))
+}
+
+// loadNames sets names of all rows (or columns) for which names[k] is
+// not empty.
+func (p *Prob) loadNames(rows bool, names []string) {
+	var buf []byte
+	off := make([]int32, len(names))
+	for k := 1; k < len(names); k++ {
+		if names[k] == "" {
+			off[k] = -1
+			continue
+		}
+		off[k] = int32(len(buf))
+		buf = append(buf, names[k]...)
+		buf = append(buf, 0)
+	}
+	if len(buf) == 0 {
+		return
+	}
+	var rowsC C.int
+	if rows {
+		rowsC = 1
+	}
+	bufH := (*reflect.SliceHeader)(unsafe.Pointer(&buf))
+	offH := (*reflect.SliceHeader)(unsafe.Pointer(&off))
+	C.load_names(p.p.p, rowsC, C.int(len(names)-1), (*C.char)(unsafe.Pointer(bufH.Data)), (*C.int)(unsafe.Pointer(offH.Data)))
+}
+
+// DelRows deletes rows num[1]..num[n] (num[0] is ignored) from the
+// problem. Rows after the deleted ones are renumbered. The row numbers
+// must be distinct and in range 1..NumRows().
+func (p *Prob) DelRows(num []int32) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(num) < 2 {
+		return
+	}
+	numH := (*reflect.SliceHeader)(unsafe.Pointer(&num))
+	C.glp_del_rows(p.p.p, C.int(len(num)-1), (*C.int)(unsafe.Pointer(numH.Data)))
+}
+
 // TODO:
 // glp_check_dup
-// glp_del_rows
 
 // Copy returns a copy of the given optimization problem. If name is
 // true also symbolic names are copies otherwise their not copied
@@ -293,7 +932,7 @@ func (p *Prob) Copy(names bool) *Prob {
 	if p.p.p == nil {
 		panic("Prob method called on a deleted problem")
 	}
-	q := &Prob{&prob{C.glp_create_prob()}}
+	q := New()
 	var namesC C.int
 	if names {
 		namesC = C.GLP_ON
@@ -304,6 +943,89 @@ func (p *Prob) Copy(names bool) *Prob {
 	return q
 }
 
+// Relaxation returns a copy of the problem (including symbolic names)
+// with all columns continuous, i.e. its LP relaxation.
+func (p *Prob) Relaxation() *Prob {
+	q := p.Copy(true)
+	n := q.NumCols()
+	for j := 1; j <= n; j++ {
+		q.SetColKind(j, CV)
+	}
+	return q
+}
+
+// WithRelaxed makes the columns js[0], js[1], ... continuous (CV),
+// calls f, and restores their kinds (also if f panics), e.g. to solve
+// the problem with only some integer columns relaxed in fix-and-optimize
+// heuristics. Returns the error returned by f.
+func (p *Prob) WithRelaxed(js []int, f func() error) error {
+	kinds := make([]VarType, len(js))
+	for k, j := range js {
+		kinds[k] = p.ColKind(j)
+	}
+	defer func() {
+		for k, j := range js {
+			p.SetColKind(j, kinds[k])
+		}
+	}()
+	for _, j := range js {
+		p.SetColKind(j, CV)
+	}
+	return f()
+}
+
+// MIPFeasibilityHint solves the LP relaxation of the problem (see
+// Relaxation) with Prob.Simplex() and checks whether its optimal
+// solution is already integral, i.e. whether the value of each integer
+// column is within 1e-9 of an integer (the same tolerance as used for
+// Iocp.SetInitialSolution()). If it returns true the solution of the
+// relaxation is an optimal solution of the MIP problem, so no
+// branching is needed and the problem may be solved with
+// Prob.Simplex() alone (or Prob.Intopt() finishes at the root). The
+// problem itself is not modified. Returns ErrNotOptimal if the
+// relaxation has no optimal solution (e.g. it is infeasible, and then
+// so is the MIP problem), or an error returned by Prob.Simplex().
+func (p *Prob) MIPFeasibilityHint() (bool, error) {
+	q := p.Relaxation()
+	defer q.Delete()
+	if err := q.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		return false, err
+	}
+	if q.Status() != OPT {
+		return false, ErrNotOptimal
+	}
+	n := p.NumCols()
+	for j := 1; j <= n; j++ {
+		if v := q.ColPrim(j); p.ColKind(j) != CV && math.Abs(v-math.Floor(v+0.5)) > feasTol {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// RootDuals solves the LP relaxation of the problem (see Relaxation)
+// with Prob.Simplex() and returns dual values of its rows (as
+// RowDual): duals[1]..duals[m] are dual values of the rows and
+// duals[0] is ignored. The problem itself is not modified. Returns
+// ErrNotOptimal if the relaxation has no optimal solution, or an
+// error returned by Prob.Simplex().
+func (p *Prob) RootDuals() ([]float64, error) {
+	q := p.Relaxation()
+	defer q.Delete()
+	if err := q.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		return nil, err
+	}
+	if q.Status() != OPT {
+		return nil, ErrNotOptimal
+	}
+	m := q.NumRows()
+	duals := make([]float64, m+1)
+	for i := 1; i <= m; i++ {
+		duals[i] = q.RowDual(i)
+	}
+	return duals, nil
+}
+
 // ProbName returns problem name.
 func (p *Prob) ProbName() string {
 	if p.p.p == nil {
@@ -320,6 +1042,22 @@ func (p *Prob) ObjName() string {
 	return C.GoString(C.glp_get_obj_name(p.p.p))
 }
 
+// SetObjRowName sets the name of the objective row, i.e. the free row
+// (N row) holding the objective function in MPS format and the name of
+// the objective in CPLEX LP format. GLPK does not store the objective
+// as a row of the problem: the name of the objective row is the
+// objective function name, so SetObjRowName is the same as SetObjName
+// (and ObjName returns it). If the objective has no name (or the name
+// is not valid in the given format) GLPK, and so glpsol, writes a
+// generated name instead ("R0000000" in MPS format and "obj" in CPLEX
+// LP format), which is why the name of the objective row differs
+// between exports of problems without an objective name. When reading
+// MPS format the name of the first free row (used as the objective)
+// becomes the objective function name.
+func (p *Prob) SetObjRowName(name string) {
+	p.SetObjName(name)
+}
+
 // ObjDir returns optimization direction (either glpk.MAX or glpk.MIN).
 func (p *Prob) ObjDir() ObjDir {
 	if p.p.p == nil {
@@ -368,6 +1106,52 @@ func (p *Prob) ColKind(j int) VarType {
 	return VarType(C.glp_get_col_kind(p.p.p, C.int(j)))
 }
 
+// NumInt returns the number of integer columns (including binary
+// columns).
+func (p *Prob) NumInt() int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_num_int(p.p.p))
+}
+
+// HasIntegerVars checks whether the problem has integer (or binary)
+// columns, i.e. whether it is a MIP problem (see NumInt).
+func (p *Prob) HasIntegerVars() bool {
+	return p.NumInt() > 0
+}
+
+// NumBin returns the number of binary columns.
+func (p *Prob) NumBin() int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_num_bin(p.p.p))
+}
+
+// ProbClass specifies the class of a problem (see Prob.Class()).
+type ProbClass int
+
+// Allowed values of type ProbClass (problem class).
+const (
+	ClassLP  ProbClass = iota // linear programming problem (all columns continuous)
+	ClassMIP                  // mixed integer problem (some columns integer or binary)
+)
+
+// Class returns glpk.ClassMIP if the problem has integer (or binary)
+// columns (see NumInt) and glpk.ClassLP otherwise. Note that the class
+// changes when the kind of a column changes or integer columns are
+// added or deleted.
+func (p *Prob) Class() ProbClass {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if C.glp_get_num_int(p.p.p) > 0 {
+		return ClassMIP
+	}
+	return ClassLP
+}
+
 // RowType returns the type of i-th row, i.e. the type of the
 // corresponding auxiliary variable.
 func (p *Prob) RowType(i int) BndsType {
@@ -426,6 +1210,136 @@ func (p *Prob) ColUB(j int) float64 {
 	return float64(C.glp_get_col_ub(p.p.p, C.int(j)))
 }
 
+// RowInfo describes a row (constraint), see Prob.Rows().
+type RowInfo struct {
+	Index  int      // row number
+	Name   string   // row name
+	Type   BndsType // bounds type
+	LB, UB float64  // lower and upper bounds (as returned by Prob.RowLB() and Prob.RowUB())
+}
+
+// ColInfo describes a column (structural variable), see Prob.Cols().
+type ColInfo struct {
+	Index  int      // column number
+	Name   string   // column name
+	Kind   VarType  // column kind
+	Type   BndsType // bounds type
+	LB, UB float64  // lower and upper bounds (as returned by Prob.ColLB() and Prob.ColUB())
+}
+
+// Rows returns descriptions of all the rows: the i-th row is
+// described by the (i-1)-th element of the result.
+func (p *Prob) Rows() []RowInfo {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	rows := make([]RowInfo, int(C.glp_get_num_rows(p.p.p)))
+	for k := range rows {
+		i := C.int(k + 1)
+		rows[k] = RowInfo{
+			Index: k + 1,
+			Name:  C.GoString(C.glp_get_row_name(p.p.p, i)),
+			Type:  BndsType(C.glp_get_row_type(p.p.p, i)),
+			LB:    float64(C.glp_get_row_lb(p.p.p, i)),
+			UB:    float64(C.glp_get_row_ub(p.p.p, i)),
+		}
+	}
+	return rows
+}
+
+// Cols returns descriptions of all the columns: the j-th column is
+// described by the (j-1)-th element of the result.
+func (p *Prob) Cols() []ColInfo {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	cols := make([]ColInfo, int(C.glp_get_num_cols(p.p.p)))
+	for k := range cols {
+		j := C.int(k + 1)
+		cols[k] = ColInfo{
+			Index: k + 1,
+			Name:  C.GoString(C.glp_get_col_name(p.p.p, j)),
+			Kind:  VarType(C.glp_get_col_kind(p.p.p, j)),
+			Type:  BndsType(C.glp_get_col_type(p.p.p, j)),
+			LB:    float64(C.glp_get_col_lb(p.p.p, j)),
+			UB:    float64(C.glp_get_col_ub(p.p.p, j)),
+		}
+	}
+	return cols
+}
+
+// Column describes a whole column (structural variable), see
+// Prob.Column().
+type Column struct {
+	Name   string    // column name
+	Kind   VarType   // column kind
+	Type   BndsType  // bounds type
+	LB, UB float64   // lower and upper bounds (as returned by Prob.ColLB() and Prob.ColUB())
+	Coef   float64   // objective function coefficient
+	Ind    []int32   // row numbers of nonzero elements (Ind[0] is ignored, as for Prob.MatCol())
+	Val    []float64 // values of nonzero elements (Val[0] is ignored)
+}
+
+// Column returns the description of j-th column including its
+// objective function coefficient and nonzero elements (see MatCol).
+func (p *Prob) Column(j int) Column {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	jC := C.int(j)
+	c := Column{
+		Name: C.GoString(C.glp_get_col_name(p.p.p, jC)),
+		Kind: VarType(C.glp_get_col_kind(p.p.p, jC)),
+		Type: BndsType(C.glp_get_col_type(p.p.p, jC)),
+		LB:   float64(C.glp_get_col_lb(p.p.p, jC)),
+		UB:   float64(C.glp_get_col_ub(p.p.p, jC)),
+		Coef: float64(C.glp_get_obj_coef(p.p.p, jC)),
+	}
+	c.Ind, c.Val = p.MatCol(j)
+	return c
+}
+
+// AppendColumns adds copies of the columns js[0], js[1], ... of src
+// (with their names, kinds, bounds, objective function coefficients,
+// and nonzero elements) as new columns of p. Rows of src are matched
+// with rows of p by name. Returns an error (and does not modify p) if
+// a row of src with a nonzero element in one of the columns has no
+// name or there is no row with that name in p.
+func (p *Prob) AppendColumns(src *Prob, js []int) error {
+	rows, _ := p.NameIndex()
+	cols := make([]Column, len(js))
+	for k, j := range js {
+		c := src.Column(j)
+		for l := 1; l < len(c.Ind); l++ {
+			name := src.RowName(int(c.Ind[l]))
+			if name == "" {
+				return errors.New("row " + strconv.Itoa(int(c.Ind[l])) + " of the source problem has no name")
+			}
+			i, ok := rows[name]
+			if !ok {
+				return errors.New("row " + strconv.Quote(name) + " not found")
+			}
+			c.Ind[l] = int32(i)
+		}
+		cols[k] = c
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+	first := p.AddCols(len(cols))
+	for k, c := range cols {
+		j := first + k
+		if c.Name != "" {
+			p.SetColName(j, c.Name)
+		}
+		p.SetColKind(j, c.Kind)
+		p.SetColBnds(j, c.Type, c.LB, c.UB)
+		p.SetObjCoef(j, c.Coef)
+		p.SetMatCol(j, c.Ind, c.Val)
+	}
+	return nil
+}
+
 // ObjCoef returns objective function coefficient of j-th column.
 func (p *Prob) ObjCoef(j int) float64 {
 	if p.p.p == nil {
@@ -434,8 +1348,77 @@ func (p *Prob) ObjCoef(j int) float64 {
 	return float64(C.glp_get_obj_coef(p.p.p, C.int(j)))
 }
 
-// TODO:
-// glp_get_num_nz
+// ObjCoefs returns all objective function coefficients: coefs[0] is
+// the constant term and coefs[1]..coefs[n] are coefficients of the
+// columns (see ObjCoef).
+func (p *Prob) ObjCoefs() []float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	n := int(C.glp_get_num_cols(p.p.p))
+	coefs := make([]float64, n+1)
+	for j := 0; j <= n; j++ {
+		coefs[j] = float64(C.glp_get_obj_coef(p.p.p, C.int(j)))
+	}
+	return coefs
+}
+
+// NumNz returns the number of nonzero elements in the constraint
+// matrix.
+func (p *Prob) NumNz() int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_num_nz(p.p.p))
+}
+
+// Stats returns the number of rows, columns, and nonzero elements of
+// the constraint matrix.
+func (p *Prob) Stats() (rows, cols, nz int) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_num_rows(p.p.p)), int(C.glp_get_num_cols(p.p.p)), int(C.glp_get_num_nz(p.p.p))
+}
+
+// NumEqualities returns the number of equality constraints, i.e. rows
+// of type glpk.FX.
+func (p *Prob) NumEqualities() int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	cnt := 0
+	m := int(C.glp_get_num_rows(p.p.p))
+	for i := 1; i <= m; i++ {
+		if BndsType(C.glp_get_row_type(p.p.p, C.int(i))) == FX {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// PresolvedStats runs the LP presolver (the same as used by Simplex
+// with Smcp.SetPresolve(true)) on a copy of the problem and returns
+// the number of rows, columns, and nonzero elements of the constraint
+// matrix of the resulting (reduced) problem. The problem itself is not
+// modified. Comparing the result with Stats is helpful e.g. to find
+// out how much of the model is redundant.
+//
+// Returns an error which is an instance of OptError: glpk.ENOPFS if
+// the presolver detected that the problem has no primal feasible
+// solution, glpk.ENODFS if it has no dual feasible solution, or
+// glpk.EFAIL if GLPK is too old to provide its presolver API (it
+// requires GLPK 4.58 or later).
+func (p *Prob) PresolvedStats() (rows, cols, nz int, err error) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	var m, n, nnz C.int
+	if e := OptError(C.presolved_stats(p.p.p, &m, &n, &nnz)); e != 0 {
+		return 0, 0, 0, e
+	}
+	return int(m), int(n), int(nnz), nil
+}
 
 // MatRow returns nonzero elements of i-th row. ind[1]..ind[n] are
 // column numbers of the nonzero elements of the row, val[1]..val[n]
@@ -457,6 +1440,24 @@ func (p *Prob) MatRow(i int) (ind []int32, val []float64) {
 	return
 }
 
+// RowNnz returns the number of nonzero elements of i-th row (without
+// allocating them as MatRow does).
+func (p *Prob) RowNnz(i int) int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_mat_row(p.p.p, C.int(i), nil, nil))
+}
+
+// ColNnz returns the number of nonzero elements of j-th column
+// (without allocating them as MatCol does).
+func (p *Prob) ColNnz(j int) int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_mat_col(p.p.p, C.int(j), nil, nil))
+}
+
 // MatCol returns nonzero elements of j-th column. ind[1]..ind[n] are
 // row numbers of the nonzero elements of the column, val[1]..val[n]
 // are their values, and n is the number of nonzero elements in the
@@ -465,29 +1466,350 @@ func (p *Prob) MatCol(j int) (ind []int32, val []float64) {
 	if p.p.p == nil {
 		panic("Prob method called on a deleted problem")
 	}
-	if len(ind) != len(val) {
-		panic("len(ind) and len(val) should be equal")
+	if len(ind) != len(val) {
+		panic("len(ind) and len(val) should be equal")
+	}
+	length := C.glp_get_mat_col(p.p.p, C.int(j), nil, nil)
+	ind = make([]int32, length+1)
+	val = make([]float64, length+1)
+	indH := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
+	valH := (*reflect.SliceHeader)(unsafe.Pointer(&val))
+	C.glp_get_mat_col(p.p.p, C.int(j), (*C.int)(unsafe.Pointer(indH.Data)), (*C.double)(unsafe.Pointer(valH.Data)))
+	return
+}
+
+// MatRow0 is like MatRow but the returned slices have no ignored
+// leading element: ind[k] and val[k] for k=0..n-1 are the column
+// numbers and values of the n nonzero elements of i-th row.
+func (p *Prob) MatRow0(i int) (ind []int32, val []float64) {
+	ind, val = p.MatRow(i)
+	return ind[1:], val[1:]
+}
+
+// MatCol0 is like MatCol but the returned slices have no ignored
+// leading element: ind[k] and val[k] for k=0..n-1 are the row numbers
+// and values of the n nonzero elements of j-th column.
+func (p *Prob) MatCol0(j int) (ind []int32, val []float64) {
+	ind, val = p.MatCol(j)
+	return ind[1:], val[1:]
+}
+
+// Coef returns the element matrix[i, j] of the constraint matrix (0
+// if the element is not stored). It scans the shorter of the i-th row
+// and the j-th column.
+func (p *Prob) Coef(i, j int) float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if C.glp_get_mat_row(p.p.p, C.int(i), nil, nil) <= C.glp_get_mat_col(p.p.p, C.int(j), nil, nil) {
+		ind, val := p.MatRow(i)
+		for k := 1; k < len(ind); k++ {
+			if int(ind[k]) == j {
+				return val[k]
+			}
+		}
+	} else {
+		ind, val := p.MatCol(j)
+		for k := 1; k < len(ind); k++ {
+			if int(ind[k]) == i {
+				return val[k]
+			}
+		}
+	}
+	return 0
+}
+
+// SetCoef sets the element matrix[i, j] of the constraint matrix to v
+// (v = 0 removes the element). It reads the i-th row, updates it, and
+// sets it with SetMatRow so each call takes time proportional to the
+// length of the row. To set many elements use SetMatRow, SetMatCol,
+// or LoadMatrix instead.
+func (p *Prob) SetCoef(i, j int, v float64) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	ind, val := p.MatRow(i)
+	k := 1
+	for k < len(ind) && int(ind[k]) != j {
+		k++
+	}
+	switch {
+	case k < len(ind) && v != 0:
+		val[k] = v
+	case k < len(ind):
+		ind = append(ind[:k], ind[k+1:]...)
+		val = append(val[:k], val[k+1:]...)
+	case v != 0:
+		ind = append(ind, int32(j))
+		val = append(val, v)
+	default:
+		return // removing an element which is not stored
+	}
+	p.SetMatRow(i, ind, val)
+}
+
+// ForEachNonzero calls f for each nonzero element matrix[i, j] = v of
+// the constraint matrix. The elements are visited column by column
+// (in the order of increasing j), within a column in the order they
+// are stored by GLPK (which is not necessarily sorted by i). It uses a
+// single buffer for all the columns so it is suitable for scanning
+// huge matrices. f must not modify the constraint matrix.
+func (p *Prob) ForEachNonzero(f func(i, j int, v float64)) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	m := int(C.glp_get_num_rows(p.p.p))
+	n := int(C.glp_get_num_cols(p.p.p))
+	ind := make([]int32, m+1)
+	val := make([]float64, m+1)
+	indH := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
+	valH := (*reflect.SliceHeader)(unsafe.Pointer(&val))
+	for j := 1; j <= n; j++ {
+		length := int(C.glp_get_mat_col(p.p.p, C.int(j), (*C.int)(unsafe.Pointer(indH.Data)), (*C.double)(unsafe.Pointer(valH.Data))))
+		for k := 1; k <= length; k++ {
+			f(int(ind[k]), j, val[k])
+		}
+	}
+}
+
+// EmptyRows returns numbers of rows which have no nonzero elements in
+// the constraint matrix. Such rows are often a result of a mistake in
+// the model and may make the problem trivially infeasible or
+// degenerate.
+func (p *Prob) EmptyRows() []int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	var rows []int
+	m := int(C.glp_get_num_rows(p.p.p))
+	allEmpty := C.glp_get_num_nz(p.p.p) == 0
+	for i := 1; i <= m; i++ {
+		if allEmpty || C.glp_get_mat_row(p.p.p, C.int(i), nil, nil) == 0 {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+// EmptyCols returns numbers of columns which have no nonzero elements
+// in the constraint matrix. Such columns are often a result of a
+// mistake in the model and make the problem unbounded if they have a
+// nonzero objective coefficient in the direction of optimization and
+// no bound in that direction.
+func (p *Prob) EmptyCols() []int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	var cols []int
+	n := int(C.glp_get_num_cols(p.p.p))
+	allEmpty := C.glp_get_num_nz(p.p.p) == 0
+	for j := 1; j <= n; j++ {
+		if allEmpty || C.glp_get_mat_col(p.p.p, C.int(j), nil, nil) == 0 {
+			cols = append(cols, j)
+		}
+	}
+	return cols
+}
+
+// CreateIndex creates the name index of the problem which allows
+// FindRow and FindCol to find rows and columns by name quickly. The
+// index is updated by GLPK as rows and columns are added, deleted, or
+// renamed. Calling CreateIndex when the index already exists has no
+// effect.
+func (p *Prob) CreateIndex() {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_create_index(p.p.p)
+}
+
+// DeleteIndex deletes the name index of the problem (to free the
+// memory it uses). It has no effect if the index does not exist.
+func (p *Prob) DeleteIndex() {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_delete_index(p.p.p)
+}
+
+// FindRow returns the number of the row with the given name or 0 if
+// there is no such row. It creates the name index (see CreateIndex)
+// if it does not exist yet.
+func (p *Prob) FindRow(name string) int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_create_index(p.p.p)
+	s := C.CString(name)
+	defer C.free(unsafe.Pointer(s))
+	return int(C.glp_find_row(p.p.p, s))
+}
+
+// FindCol returns the number of the column with the given name or 0
+// if there is no such column. It creates the name index (see
+// CreateIndex) if it does not exist yet.
+func (p *Prob) FindCol(name string) int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_create_index(p.p.p)
+	s := C.CString(name)
+	defer C.free(unsafe.Pointer(s))
+	return int(C.glp_find_col(p.p.p, s))
+}
+
+// NameIndex returns maps from names of rows and columns to their
+// numbers (rows and columns without names are omitted). The maps are
+// a snapshot: they are not updated when rows or columns are later
+// added, deleted, or renamed (use FindRow and FindCol for lookups
+// which reflect the current state of the problem). The maps are built
+// from the names of the rows and columns (the name index of
+// CreateIndex is not used). If several rows (or columns) have the same
+// name the map holds the first of them.
+func (p *Prob) NameIndex() (rows, cols map[string]int) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	m := int(C.glp_get_num_rows(p.p.p))
+	rows = make(map[string]int, m)
+	for i := 1; i <= m; i++ {
+		if s := C.glp_get_row_name(p.p.p, C.int(i)); s != nil {
+			if name := C.GoString(s); rows[name] == 0 {
+				rows[name] = i
+			}
+		}
+	}
+	n := int(C.glp_get_num_cols(p.p.p))
+	cols = make(map[string]int, n)
+	for j := 1; j <= n; j++ {
+		if s := C.glp_get_col_name(p.p.p, C.int(j)); s != nil {
+			if name := C.GoString(s); cols[name] == 0 {
+				cols[name] = j
+			}
+		}
+	}
+	return rows, cols
+}
+
+// TODO:
+// glp_set_rii
+// glp_set_sjj
+
+// ScaleFlags specifies scaling options for Prob.ScaleProb().
+type ScaleFlags int
+
+// Allowed values of type ScaleFlags (scaling options, may be combined
+// with bitwise or).
+const (
+	SF_GM   = ScaleFlags(C.GLP_SF_GM)   // perform geometric mean scaling
+	SF_EQ   = ScaleFlags(C.GLP_SF_EQ)   // perform equilibration scaling
+	SF_2N   = ScaleFlags(C.GLP_SF_2N)   // round scale factors to the nearest power of two
+	SF_SKIP = ScaleFlags(C.GLP_SF_SKIP) // skip scaling, if the problem is well scaled
+	SF_AUTO = ScaleFlags(C.GLP_SF_AUTO) // choose scaling options automatically
+)
+
+// ScaleProb scales the constraint matrix of the problem. Scaling is
+// internal to GLPK: problem data and solution values returned by the
+// glpk package (e.g. ObjVal, ColPrim, MatRow) are always in the
+// original (unscaled) units.
+func (p *Prob) ScaleProb(flags ScaleFlags) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_scale_prob(p.p.p, C.int(flags))
+}
+
+// UnscaleProb sets all scale factors of the problem to 1.
+func (p *Prob) UnscaleProb() {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_unscale_prob(p.p.p)
+}
+
+// SimplexScaled scales the problem with ScaleProb(flags) (zero flags
+// mean glpk.SF_AUTO), solves it with Simplex(parm), and then removes
+// the scaling with UnscaleProb. Scaling improves numerical stability
+// of the simplex method for badly scaled problems. The solution is, as
+// always, reported in the original units. Note that removing the
+// scaling invalidates the basis factorization (but not the basis nor
+// the solution).
+func (p *Prob) SimplexScaled(parm *Smcp, flags ScaleFlags) error {
+	if flags == 0 {
+		flags = SF_AUTO
+	}
+	p.ScaleProb(flags)
+	defer p.UnscaleProb()
+	return p.Simplex(parm)
+}
+
+// FindFeasible checks whether the problem has a (primal) feasible
+// solution by solving it with Prob.Simplex() with the objective
+// function temporarily replaced by zero. The objective function is
+// restored before returning. If it returns true the basic solution is
+// the feasible point found (which is not optimal for the restored
+// objective). Integrality of the columns is ignored. parm may be nil
+// (as for Prob.Simplex()). Returns an error returned by Prob.Simplex()
+// other than glpk.ENOPFS (which means there is no feasible solution).
+func (p *Prob) FindFeasible(parm *Smcp) (bool, error) {
+	coefs := p.ObjCoefs()
+	js := make([]int, len(coefs))
+	for j := range js {
+		js[j] = j
+	}
+	p.SetObjCoefs(js, make([]float64, len(coefs)))
+	defer p.SetObjCoefs(js, coefs)
+	if err := p.Simplex(parm); err != nil {
+		if err == ENOPFS {
+			return false, nil
+		}
+		return false, err
+	}
+	return p.PrimStat() == FEAS, nil
+}
+
+// SolveWithFixed fixes each column j in fixed at value fixed[j] (i.e.
+// sets its bounds to FX) and solves the problem with
+// Prob.Simplex(parm). By default the original bounds of the fixed
+// columns are restored after solving (also if Prob.Simplex() fails),
+// so that only the basic solution reflects the fixing; with keep set
+// to true the columns are left fixed instead. In both cases the basic
+// solution is the one of the problem with the columns fixed (it need
+// not be optimal for the restored problem). parm may be nil (as for
+// Prob.Simplex()). Returns an error returned by Prob.Simplex().
+func (p *Prob) SolveWithFixed(fixed map[int]float64, keep bool, parm *Smcp) error {
+	if !keep {
+		bnds := make(map[int]ColInfo, len(fixed))
+		for j := range fixed {
+			bnds[j] = ColInfo{Type: p.ColType(j), LB: p.ColLB(j), UB: p.ColUB(j)}
+		}
+		defer func() {
+			for j, b := range bnds {
+				p.SetColBnds(j, b.Type, b.LB, b.UB)
+			}
+		}()
+	}
+	for j, v := range fixed {
+		p.SetColBnds(j, FX, v, v)
+	}
+	return p.Simplex(parm)
+}
+
+// RowScale returns the scale factor of i-th row.
+func (p *Prob) RowScale(i int) float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
 	}
-	length := C.glp_get_mat_col(p.p.p, C.int(j), nil, nil)
-	ind = make([]int32, length+1)
-	val = make([]float64, length+1)
-	indH := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
-	valH := (*reflect.SliceHeader)(unsafe.Pointer(&val))
-	C.glp_get_mat_col(p.p.p, C.int(j), (*C.int)(unsafe.Pointer(indH.Data)), (*C.double)(unsafe.Pointer(valH.Data)))
-	return
+	return float64(C.glp_get_rii(p.p.p, C.int(i)))
 }
 
-// TODO:
-// glp_create_index
-// glp_find_row
-// glp_find_col
-// glp_delete_index
-// glp_set_rii
-// glp_set_sjj
-// glp_get_rii
-// glp_get_sjj
-// glp_scale_prob
-// glp_unscale_prob
+// ColScale returns the scale factor of j-th column.
+func (p *Prob) ColScale(j int) float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_sjj(p.p.p, C.int(j)))
+}
 
 // VarStat represents status of auxiliary/structural variable.
 type VarStat int
@@ -519,8 +1841,145 @@ func (p *Prob) SetColStat(j int, stat VarStat) {
 	C.glp_set_col_stat(p.p.p, C.int(j), C.int(stat))
 }
 
+// SetColStats sets the statuses of all the columns: the j-th column
+// gets status stats[j] for j=1..n (stats[0] is ignored), see
+// SetColStat. Note that together with the statuses of the rows the
+// statuses must define a valid basis (exactly NumRows() basic
+// variables with a nonsingular basis matrix, see Factorize) for
+// Prob.Simplex() to start from it, otherwise it returns glpk.EBADB or
+// glpk.ESING. Requires len(stats) = NumCols()+1.
+func (p *Prob) SetColStats(stats []VarStat) {
+	n := p.NumCols()
+	if len(stats) != n+1 {
+		panic("len(stats) should be equal to the number of columns plus one")
+	}
+	for j := 1; j <= n; j++ {
+		p.SetColStat(j, stats[j])
+	}
+}
+
+// GuessBasis sets the statuses of the rows and of the columns from a
+// candidate solution x[1]..x[n] (values of the columns, x[0] is
+// ignored), e.g. a solution of a similar problem, so that the next
+// call to Prob.Simplex() (without the presolver, which discards the
+// basis) starts close to it and needs fewer iterations. A column (or a
+// row with its activity computed from the constraint matrix) at its
+// bound (up to the relative tolerance 1e-9) becomes non-basic on that
+// bound and other ones become basic. Then, as a valid basis must have
+// exactly NumRows() basic variables, non-basic rows are made basic if
+// there are too few basic variables, and basic rows and then basic
+// columns with bounds are made non-basic on the nearest bound if there
+// are too many (in order of their numbers), which means that the
+// basic solution may differ from x. Returns an error returned by
+// Factorize if the resulting basis matrix is singular (then e.g.
+// AdvBasis may be used instead). Requires len(x) = NumCols()+1.
+func (p *Prob) GuessBasis(x []float64) error {
+	m, n := p.NumRows(), p.NumCols()
+	if len(x) != n+1 {
+		panic("len(x) should be equal to the number of columns plus one")
+	}
+	row := make([]float64, m+1)
+	p.ForEachNonzero(func(i, j int, v float64) {
+		row[i] += v * x[j]
+	})
+	rowStats := make([]VarStat, m+1)
+	colStats := make([]VarStat, n+1)
+	basic := 0
+	for i := 1; i <= m; i++ {
+		rowStats[i] = guessStat(row[i], p.RowType(i), p.RowLB(i), p.RowUB(i))
+		if rowStats[i] == BS {
+			basic++
+		}
+	}
+	for j := 1; j <= n; j++ {
+		colStats[j] = guessStat(x[j], p.ColType(j), p.ColLB(j), p.ColUB(j))
+		if colStats[j] == BS {
+			basic++
+		}
+	}
+	for i := 1; i <= m && basic < m; i++ {
+		if rowStats[i] != BS {
+			rowStats[i] = BS
+			basic++
+		}
+	}
+	for i := 1; i <= m && basic > m; i++ {
+		if typ := p.RowType(i); rowStats[i] == BS && typ != FR {
+			rowStats[i] = nearestStat(row[i], typ, p.RowLB(i), p.RowUB(i))
+			basic--
+		}
+	}
+	for j := 1; j <= n && basic > m; j++ {
+		if typ := p.ColType(j); colStats[j] == BS && typ != FR {
+			colStats[j] = nearestStat(x[j], typ, p.ColLB(j), p.ColUB(j))
+			basic--
+		}
+	}
+	for i := 1; i <= m; i++ {
+		p.SetRowStat(i, rowStats[i])
+	}
+	p.SetColStats(colStats)
+	return p.Factorize()
+}
+
+// guessStat returns the status of a variable with value v and bounds
+// of type typ for GuessBasis: non-basic if v is at a bound and basic
+// otherwise.
+func guessStat(v float64, typ BndsType, lb, ub float64) VarStat {
+	const eps = 1e-9
+	atLB := math.Abs(v-lb) <= eps*math.Max(1, math.Abs(lb))
+	atUB := math.Abs(v-ub) <= eps*math.Max(1, math.Abs(ub))
+	switch {
+	case typ == FX && atLB:
+		return NS
+	case (typ == LO || typ == DB) && atLB:
+		return NL
+	case (typ == UP || typ == DB) && atUB:
+		return NU
+	case typ == FR && v == 0:
+		return NF
+	}
+	return BS
+}
+
+// nearestStat returns the non-basic status for the bound (of type typ,
+// other than FR) nearest to v.
+func nearestStat(v float64, typ BndsType, lb, ub float64) VarStat {
+	switch typ {
+	case FX:
+		return NS
+	case LO:
+		return NL
+	case UP:
+		return NU
+	}
+	if v-lb <= ub-v {
+		return NL
+	}
+	return NU
+}
+
+// AdvBasis constructs an advanced initial LP basis for the problem
+// (a "crash" basis), which is used by the next call to Simplex. It is
+// equivalent to AdvBasisFlags(0).
+func (p *Prob) AdvBasis() {
+	p.AdvBasisFlags(0)
+}
+
+// AdvBasisFlags constructs an advanced initial LP basis for the
+// problem passing flags to glp_adv_basis. GLPK reserves the flags
+// argument for future use and currently requires it to be 0 (other
+// values are reported by GLPK as an error), so use AdvBasis unless
+// you target a GLPK version which documents other flags.
+func (p *Prob) AdvBasisFlags(flags int) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_adv_basis(p.p.p, C.int(flags))
+}
+
+// TODO:
 // glp_std_basis
-// glp_adv_basis
 // glp_cpx_basis
 
 // OptError represents optimization error.
@@ -604,17 +2063,94 @@ func (p *Prob) Simplex(parm *Smcp) error {
 		panic("Prob method called on a deleted problem")
 	}
 	var err OptError
+	var smcp *C.glp_smcp
+	var output io.Writer
 	if parm != nil {
-		err = OptError(C.glp_simplex(p.p.p, &parm.smcp))
-	} else {
-		err = OptError(C.glp_simplex(p.p.p, nil))
-	}
+		smcp, output = &parm.smcp, parm.output
+	}
+	withOutput(output, func() {
+		start := time.Now()
+		err = OptError(C.glp_simplex(p.p.p, smcp))
+		p.p.solveTime = time.Since(start)
+		p.p.solveErr, p.p.solveMip, p.p.solved = err, false, true
+	})
 	if err == 0 {
 		return nil
 	}
 	return err
 }
 
+// ReSolveDual re-solves the problem with the dual simplex method
+// starting from the current basis. It is intended for re-solving after
+// changing only bounds of rows or columns (e.g. in a loop sweeping a
+// bound), as such changes keep the optimal basis dual feasible, so the
+// dual simplex usually needs only a few iterations. It assumes that
+// the problem has a valid basis from a prior solve (with the
+// presolver disabled, as otherwise GLPK does not keep the basis).
+//
+// parm is copied (parm may be nil, which means default parameters) and
+// the copy has the simplex method set to glpk.DUALP (so that the
+// primal simplex is used if the dual simplex fails) and the presolver
+// disabled (as it would discard the basis). Returns an error as
+// Prob.Simplex().
+func (p *Prob) ReSolveDual(parm *Smcp) error {
+	if parm == nil {
+		parm = NewSmcp()
+	} else {
+		parm = parm.Clone()
+	}
+	parm.SetMeth(DUALP)
+	parm.SetPresolve(false)
+	return p.Simplex(parm)
+}
+
+// SolveObjectiveSweep solves the problem for each of the objectives
+// objs[0], objs[1], ... (in the format of ObjCoefs: objs[k][0] is the
+// constant term and objs[k][1..n] are coefficients of the columns) and
+// returns the optimal objective function values. The objective
+// direction and the constraints do not change, so the optimal basis
+// for one objective stays primal feasible for the next one and each
+// solve is warm-started from it: parm is copied (nil means default
+// parameters) with the presolver disabled (as it would discard the
+// basis) and the primal simplex (the default method) is the best
+// choice. After the sweep the original objective is restored but the
+// basic solution is the one for the last objective.
+//
+// If a solve fails or finds no optimal solution, the sweep stops and
+// the values computed so far are returned with the error returned by
+// Prob.Simplex() or ErrNotOptimal. Requires len(objs[k]) = NumCols()+1.
+func (p *Prob) SolveObjectiveSweep(objs [][]float64, parm *Smcp) ([]float64, error) {
+	coefs := p.ObjCoefs()
+	for _, obj := range objs {
+		if len(obj) != len(coefs) {
+			panic("len(objs[k]) should be equal to the number of columns plus one")
+		}
+	}
+	if parm == nil {
+		parm = NewSmcp()
+	} else {
+		parm = parm.Clone()
+	}
+	parm.SetPresolve(false)
+	js := make([]int, len(coefs))
+	for j := range js {
+		js[j] = j
+	}
+	defer p.SetObjCoefs(js, coefs)
+	vals := make([]float64, 0, len(objs))
+	for _, obj := range objs {
+		p.SetObjCoefs(js, obj)
+		if err := p.Simplex(parm); err != nil {
+			return vals, err
+		}
+		if p.Status() != OPT {
+			return vals, ErrNotOptimal
+		}
+		vals = append(vals, p.ObjVal())
+	}
+	return vals, nil
+}
+
 // Exact solves LP with Simplex method using exact (rational)
 // arithmetic. argument parm may by nil (means that default values
 // will be used). See also NewSmcp().  Returns nil if problem have
@@ -625,33 +2161,70 @@ func (p *Prob) Exact(parm *Smcp) error {
 		panic("Prob method called on a deleted problem")
 	}
 	var err OptError
+	var smcp *C.glp_smcp
+	var output io.Writer
 	if parm != nil {
-		err = OptError(C.glp_exact(p.p.p, &parm.smcp))
-	} else {
-		err = OptError(C.glp_exact(p.p.p, nil))
-	}
+		smcp, output = &parm.smcp, parm.output
+	}
+	withOutput(output, func() {
+		start := time.Now()
+		err = OptError(C.glp_exact(p.p.p, smcp))
+		p.p.solveTime = time.Since(start)
+		p.p.solveErr, p.p.solveMip, p.p.solved = err, false, true
+	})
 	if err == 0 {
 		return nil
 	}
 	return err
 }
 
+// SimplexWithExactFallback solves LP with Prob.Simplex() and, if it
+// fails because of numerical problems (glpk.EINSTAB or glpk.ECOND),
+// solves it again with Prob.Exact() starting from the basis left by
+// Prob.Simplex(). It returns nil if either of them succeeded, and
+// otherwise the error returned by the last one. The exact simplex uses
+// rational arithmetic, which is much slower (often by orders of
+// magnitude) and uses much more memory than floating point, so the
+// fallback is only suitable for small to medium sized problems. parm
+// may be nil (as for Prob.Simplex()); it is used for both solvers.
+func (p *Prob) SimplexWithExactFallback(parm *Smcp) error {
+	err := p.Simplex(parm)
+	if err == EINSTAB || err == ECOND {
+		return p.Exact(parm)
+	}
+	return err
+}
+
 // Smcp represents simplex solver control parameters, a set of
 // parameters for Prob.Simplex() and Prob.Exact(). Please use
 // NewSmcp() to create Smtp structure which is properly initialized.
 type Smcp struct {
-	smcp C.glp_smcp
+	smcp   C.glp_smcp
+	output io.Writer // GLPK terminal output (nil means stdout)
 }
 
 // NewSmcp creates new Smcp struct (a set of simplex solver control
 // parameters) to be given as argument of Prob.Simplex() or
-// Prob.Exact().
-func NewSmcp() *Smcp {
+// Prob.Exact(). The parameters have default values modified by the
+// given options (if any), e.g.
+//
+//	smcp := glpk.NewSmcp(glpk.WithMeth(glpk.DUAL), glpk.WithTmLim(5000))
+func NewSmcp(opts ...SmcpOption) *Smcp {
 	s := new(Smcp)
 	C.glp_init_smcp(&s.smcp)
+	for _, opt := range opts {
+		opt.applySmcp(s)
+	}
 	return s
 }
 
+// Clone returns a copy of the parameters, so that it can be modified
+// without affecting s.
+func (s *Smcp) Clone() *Smcp {
+	t := *s
+	return &t
+}
+
 // MsgLev represents message level.
 type MsgLev int
 
@@ -759,6 +2332,76 @@ func (s *Smcp) SetRTest(rTest RTest) {
 	s.smcp.r_test = C.int(rTest)
 }
 
+// SetObjLimits sets the lower and upper limits of the objective
+// function (default: -math.MaxFloat64 and +math.MaxFloat64, i.e. no
+// limits). They are used only by the dual simplex (see SetMeth) in
+// phase II, during which the objective function value monotonically
+// approaches the optimum from the "wrong" side (increases when
+// minimizing and decreases when maximizing). The search stops with
+// Prob.Simplex() returning glpk.EOBJUL (when minimizing) if the value
+// exceeds ul, or glpk.EOBJLL (when maximizing) if it falls below ll,
+// which means that the optimum is known to be worse than the limit.
+// The basic solution is then dual feasible but not (necessarily)
+// primal feasible, so it is not an approximate solution: GLPK offers
+// no way to stop the simplex at a feasible but suboptimal solution
+// within a relative tolerance. The closest alternative is the
+// iteration or time limit (see SetItLim and SetTmLim) with the primal
+// simplex whose basic solution stays primal feasible once it has been
+// found (check PrimStat).
+func (s *Smcp) SetObjLimits(ll, ul float64) {
+	s.smcp.obj_ll = C.double(ll)
+	s.smcp.obj_ul = C.double(ul)
+}
+
+// SetItLim sets simplex iteration limit (default: no limit). If the
+// limit is reached Prob.Simplex() returns glpk.EITLIM.
+func (s *Smcp) SetItLim(itLim int) {
+	s.smcp.it_lim = C.int(itLim)
+}
+
+// SetTmLim sets searching time limit in milliseconds (default: no
+// limit). If the limit is reached Prob.Simplex() returns glpk.ETMLIM.
+func (s *Smcp) SetTmLim(tmLim int) {
+	s.smcp.tm_lim = C.int(tmLim)
+}
+
+// SetDeterministic removes the time limit and sets the iteration limit
+// to itLim, so that the solver stops after the same number of
+// iterations (and with the same result) no matter how fast the machine
+// is.
+func (s *Smcp) SetDeterministic(itLim int) {
+	s.smcp.it_lim = C.int(itLim)
+	s.smcp.tm_lim = math.MaxInt32
+}
+
+// SetOutput redirects the GLPK terminal output of Prob.Simplex() and
+// Prob.Exact() to w (nil, the default, means stdout). As GLPK
+// terminal output is process global, solves with the output
+// redirected are serialized (other GLPK calls done concurrently may
+// also have their output redirected to w).
+func (s *Smcp) SetOutput(w io.Writer) {
+	s.output = w
+}
+
+// Presolve checks whether the LP presolver is enabled.
+func (s *Smcp) Presolve() bool {
+	return s.smcp.presolve == C.GLP_ON
+}
+
+// SetPresolve enables or disables the LP presolver (default:
+// disabled). With the presolver enabled Prob.Simplex() does not use
+// the initial basis, and returns glpk.ENOPFS or glpk.ENODFS if the
+// presolver finds that the problem has no primal or dual feasible
+// solution (in which case no basic solution is produced, see
+// Prob.HasBasis).
+func (s *Smcp) SetPresolve(on bool) {
+	if on {
+		s.smcp.presolve = C.GLP_ON
+	} else {
+		s.smcp.presolve = C.GLP_OFF
+	}
+}
+
 // Status returns status of the basic solution.
 func (p *Prob) Status() SolStat {
 	if p.p.p == nil {
@@ -783,7 +2426,50 @@ func (p *Prob) DualStat() SolStat {
 	return SolStat(C.glp_get_dual_stat(p.p.p))
 }
 
-// ObjVal returns objective function value.
+// UnboundedRay returns the number k of a variable which causes primal
+// or dual unboundedness of the basic solution found by the primal or
+// dual simplex method. If 1 <= k <= m, where m is the number of rows,
+// it is the auxiliary variable of k-th row; if m < k <= m+n, where n
+// is the number of columns, it is the structural variable of (k-m)-th
+// column (see SplitK). It returns 0 if the solution is not unbounded
+// (or the variable is not known).
+func (p *Prob) UnboundedRay() int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_unbnd_ray(p.p.p))
+}
+
+// SplitK converts number k of a variable in the combined numbering
+// used by e.g. UnboundedRay, PrimRTest, and DualRTest (1..m for
+// auxiliary variables of the rows and m+1..m+n for structural
+// variables of the columns, where m is the number of rows) into the
+// row number (isRow is true) or the column number (isRow is false).
+func (p *Prob) SplitK(k int) (isRow bool, idx int) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	m := int(C.glp_get_num_rows(p.p.p))
+	if k <= m {
+		return true, k
+	}
+	return false, k - m
+}
+
+// CombineK converts the row number (isRow is true) or the column
+// number (isRow is false) into the combined numbering (see SplitK).
+func (p *Prob) CombineK(isRow bool, idx int) int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if isRow {
+		return idx
+	}
+	return int(C.glp_get_num_rows(p.p.p)) + idx
+}
+
+// ObjVal returns objective function value. The value is in the
+// original units even if the problem is scaled (see ScaleProb).
 func (p *Prob) ObjVal() float64 {
 	if p.p.p == nil {
 		panic("Prob method called on a deleted problem")
@@ -799,9 +2485,45 @@ func (p *Prob) RowStat(i int) VarStat {
 	return VarStat(C.glp_get_row_stat(p.p.p, C.int(i)))
 }
 
-// TODO:
-// glp_get_row_prim
-// glp_get_row_dual
+// RowPrim returns primal value of the auxiliary variable associated
+// with i-th row (i.e. the value of the row's linear form).
+func (p *Prob) RowPrim(i int) float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_row_prim(p.p.p, C.int(i)))
+}
+
+// RowSlack returns slack of i-th row for the current basic solution,
+// i.e. the distance of RowPrim(i) to the row's bound: RowUB(i) -
+// RowPrim(i) for glpk.UP rows, RowPrim(i) - RowLB(i) for glpk.LO rows,
+// and the distance to the nearer bound for glpk.DB rows. For glpk.FX
+// rows it returns -|RowPrim(i) - RowLB(i)| (which is zero when the row
+// is satisfied) and for glpk.FR rows it returns +Inf. Slack is
+// negative if the row is violated.
+func (p *Prob) RowSlack(i int) float64 {
+	prim := p.RowPrim(i)
+	switch p.RowType(i) {
+	case LO:
+		return prim - p.RowLB(i)
+	case UP:
+		return p.RowUB(i) - prim
+	case FX:
+		return -math.Abs(prim - p.RowLB(i))
+	case DB:
+		return math.Min(prim-p.RowLB(i), p.RowUB(i)-prim)
+	}
+	return math.Inf(1)
+}
+
+// RowDual returns dual value (i.e. reduced cost) of the auxiliary
+// variable associated with i-th row.
+func (p *Prob) RowDual(i int) float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_row_dual(p.p.p, C.int(i)))
+}
 
 // ColStat returns the current status of j-th column structural
 // variable.
@@ -812,6 +2534,82 @@ func (p *Prob) ColStat(j int) VarStat {
 	return VarStat(C.glp_get_col_stat(p.p.p, C.int(j)))
 }
 
+// RowIsBasic checks whether the auxiliary variable of i-th row is
+// basic (i.e. RowStat(i) is glpk.BS).
+func (p *Prob) RowIsBasic(i int) bool {
+	return p.RowStat(i) == BS
+}
+
+// ColIsBasic checks whether the structural variable of j-th column is
+// basic (i.e. ColStat(j) is glpk.BS).
+func (p *Prob) ColIsBasic(j int) bool {
+	return p.ColStat(j) == BS
+}
+
+// NonzeroCols returns numbers of columns whose values in the basic
+// solution are nonzero, i.e. |ColPrim(j)| > eps (see also
+// MipNonzeroCols).
+func (p *Prob) NonzeroCols(eps float64) []int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	var cols []int
+	n := int(C.glp_get_num_cols(p.p.p))
+	for j := 1; j <= n; j++ {
+		if math.Abs(float64(C.glp_get_col_prim(p.p.p, C.int(j)))) > eps {
+			cols = append(cols, j)
+		}
+	}
+	return cols
+}
+
+// HasBasis checks whether the problem has a basic solution with a
+// valid basis, i.e. the basic solution is defined (Status is not
+// glpk.UNDEF) and the number of basic variables (see RowStat and
+// ColStat) equals the number of rows. This is not the case e.g. if
+// Prob.Simplex() with the presolver enabled (see Smcp.SetPresolve)
+// returned an error (such as glpk.ENOPFS) as then no basic solution
+// is produced and the statuses are those of the initial basis (or
+// invalid). In that case use Prob.Simplex() with the presolver
+// disabled to get a basis.
+func (p *Prob) HasBasis() bool {
+	if p.Status() == UNDEF {
+		return false
+	}
+	m, n := p.NumRows(), p.NumCols()
+	cnt := 0
+	for i := 1; i <= m; i++ {
+		if p.RowIsBasic(i) {
+			cnt++
+		}
+	}
+	for j := 1; j <= n; j++ {
+		if p.ColIsBasic(j) {
+			cnt++
+		}
+	}
+	return cnt == m
+}
+
+// Basis returns numbers of the rows and of the columns whose
+// variables are basic (see RowIsBasic and ColIsBasic) in the current
+// basis, i.e. the basis of the most recent Prob.Simplex() (unless the
+// statuses have been changed since then, e.g. with SetRowStat).
+func (p *Prob) Basis() (rows, cols []int) {
+	m, n := p.NumRows(), p.NumCols()
+	for i := 1; i <= m; i++ {
+		if p.RowIsBasic(i) {
+			rows = append(rows, i)
+		}
+	}
+	for j := 1; j <= n; j++ {
+		if p.ColIsBasic(j) {
+			cols = append(cols, j)
+		}
+	}
+	return rows, cols
+}
+
 // ColPrim returns primal value of the variable associated with j-th
 // column.
 func (p *Prob) ColPrim(j int) float64 {
@@ -821,15 +2619,333 @@ func (p *Prob) ColPrim(j int) float64 {
 	return float64(C.glp_get_col_prim(p.p.p, C.int(j)))
 }
 
+// ColDual returns dual value (i.e. reduced cost) of the variable
+// associated with j-th column.
+func (p *Prob) ColDual(j int) float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_col_dual(p.p.p, C.int(j)))
+}
+
+// ColDualsByName returns a map from names of columns to their dual
+// values (i.e. reduced costs, see ColDual). Columns without names are
+// omitted.
+func (p *Prob) ColDualsByName() map[string]float64 {
+	n := p.NumCols()
+	duals := make(map[string]float64, n)
+	for j := 1; j <= n; j++ {
+		if name := p.ColName(j); name != "" {
+			duals[name] = p.ColDual(j)
+		}
+	}
+	return duals
+}
+
+// RankByReducedCost returns the numbers of all the columns ordered by
+// their reduced costs (see ColDual) from the most promising entering
+// column, e.g. for pricing in column generation. The reduced cost of a
+// column is the rate of change of the objective function when the
+// column increases, so for minimization the columns are ordered by
+// increasing reduced costs (the most negative first) and for
+// maximization by decreasing reduced costs (the most positive first).
+// Columns with equal reduced costs are ordered by their numbers. Note
+// that increasing a column is only possible if it is not at its upper
+// bound, which is not taken into account.
+func (p *Prob) RankByReducedCost() []int {
+	n := p.NumCols()
+	r := reducedCosts{make([]int, n), make([]float64, n)}
+	sign := 1.0
+	if p.ObjDir() == MAX {
+		sign = -1
+	}
+	for k := range r.js {
+		r.js[k] = k + 1
+		r.d[k] = sign * p.ColDual(k+1)
+	}
+	sort.Stable(r)
+	return r.js
+}
+
+// reducedCosts sorts columns by their (signed) reduced costs.
+type reducedCosts struct {
+	js []int
+	d  []float64
+}
+
+func (r reducedCosts) Len() int           { return len(r.js) }
+func (r reducedCosts) Less(i, j int) bool { return r.d[i] < r.d[j] }
+func (r reducedCosts) Swap(i, j int) {
+	r.js[i], r.js[j] = r.js[j], r.js[i]
+	r.d[i], r.d[j] = r.d[j], r.d[i]
+}
+
+// ObjBound returns a bound on the optimal objective function value
+// (a lower bound when minimizing and an upper bound when maximizing)
+// computed from the current dual values (see RowDual and ColDual),
+// which is useful when Prob.Simplex() stopped before finding the
+// optimum (e.g. on the iteration or time limit). It is the Lagrangian
+// bound: the optimum over the bounds of the rows and of the columns of
+// the sum of the dual values times the values of the corresponding
+// variables, plus the constant term of the objective function (dual
+// values smaller than 1e-9 in absolute value are taken as zero). The
+// bound is infinite if a variable without a bound in the required
+// direction has a dual value of the wrong sign. It equals ObjVal if
+// the basic solution is dual feasible, in particular if it is optimal.
+// If the basic solution is undefined (see Status) the bound is
+// infinite.
+func (p *Prob) ObjBound() float64 {
+	max := p.ObjDir() == MAX
+	inf := math.Inf(-1)
+	if max {
+		inf = math.Inf(1)
+	}
+	if p.Status() == UNDEF {
+		return inf
+	}
+	bound := p.ObjCoef(0)
+	// term returns the optimum of d*x for x within the bounds (or the
+	// infinite bound)
+	term := func(d float64, typ BndsType, lb, ub float64) float64 {
+		if math.Abs(d) <= 1e-9 {
+			return 0
+		}
+		if max {
+			d = -d
+		}
+		// minimize d*x
+		var v float64
+		switch {
+		case d > 0 && (typ == LO || typ == DB || typ == FX):
+			v = d * lb
+		case d < 0 && (typ == UP || typ == DB || typ == FX):
+			v = d * ub
+		default:
+			return math.Inf(-1)
+		}
+		if max {
+			v = -v
+		}
+		return v
+	}
+	m, n := p.NumRows(), p.NumCols()
+	for i := 1; i <= m; i++ {
+		t := term(p.RowDual(i), p.RowType(i), p.RowLB(i), p.RowUB(i))
+		if math.IsInf(t, 0) {
+			return inf
+		}
+		bound += t
+	}
+	for j := 1; j <= n; j++ {
+		t := term(p.ColDual(j), p.ColType(j), p.ColLB(j), p.ColUB(j))
+		if math.IsInf(t, 0) {
+			return inf
+		}
+		bound += t
+	}
+	return bound
+}
+
+// RowDualsByName returns a map from names of rows to their dual values
+// (see RowDual). Rows without names are omitted.
+func (p *Prob) RowDualsByName() map[string]float64 {
+	m := p.NumRows()
+	duals := make(map[string]float64, m)
+	for i := 1; i <= m; i++ {
+		if name := p.RowName(i); name != "" {
+			duals[name] = p.RowDual(i)
+		}
+	}
+	return duals
+}
+
 // TODO:
-// glp_get_col_dual
 // ...
 
+// Factorize computes the factorization of the basis matrix for the
+// current basis (as specified by row and column statuses). Returns nil
+// if the factorization has been computed otherwise returns an error
+// which is an instance of OptError: glpk.EBADB if the basis is invalid
+// (the number of basic variables is not the same as the number of
+// rows), glpk.ESING if the basis matrix is singular, or glpk.ECOND if
+// the basis matrix is ill-conditioned.
+func (p *Prob) Factorize() error {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	err := OptError(C.glp_factorize(p.p.p))
+	if err != 0 {
+		return err
+	}
+	return nil
+}
+
+// BasisFactorizationValid checks whether the factorization of the
+// current basis matrix exists and is valid. It becomes invalid after
+// changing the basis, i.e. after changing statuses of rows or columns,
+// adding or removing rows or columns, or changing the constraint
+// matrix. Use Factorize to compute it.
+func (p *Prob) BasisFactorizationValid() bool {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return C.glp_bf_exists(p.p.p) != 0
+}
+
+// BasisFactorizationUpdated checks whether the valid factorization of
+// the current basis matrix has been updated (e.g. by the simplex
+// solver) since it was last computed. The updated factorization is
+// less accurate than a freshly computed one.
+func (p *Prob) BasisFactorizationUpdated() bool {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return C.glp_bf_updated(p.p.p) != 0
+}
+
+// ErrNoFactorization is returned by Prob.BasisCondition() if the
+// factorization of the basis matrix does not exist.
+var ErrNoFactorization = errors.New("basis factorization does not exist")
+
+// BasisCondition returns an estimate of the condition number (in
+// 1-norm) of the current basis matrix B, i.e. of ||B|| ||B^-1||. Large
+// values (e.g. above 1e10) indicate numerical trouble, which can be
+// reduced by scaling (see ScaleProb) or solved with exact arithmetic
+// (see Exact). GLPK does not report its own estimate, so ||B^-1|| is
+// estimated with Hager's method which requires a few solves with
+// the existing factorization (see BasisFactorizationValid). Returns
+// ErrNoFactorization if it does not exist (use Factorize to compute
+// it).
+func (p *Prob) BasisCondition() (float64, error) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if C.glp_bf_exists(p.p.p) == 0 {
+		return 0, ErrNoFactorization
+	}
+	m := int(C.glp_get_num_rows(p.p.p))
+	if m == 0 {
+		return 0, ErrNoFactorization
+	}
+
+	// columns of B are columns of (I | -A) of the basic variables
+	normB := 0.0
+	for k := 1; k <= m; k++ {
+		norm := 1.0
+		if h := int(C.glp_get_bhead(p.p.p, C.int(k))); h > m {
+			_, val := p.MatCol(h - m)
+			norm = 0
+			for _, v := range val[1:] {
+				norm += math.Abs(v)
+			}
+		}
+		normB = math.Max(normB, norm)
+	}
+
+	// Hager's estimate of ||B^-1||
+	x := make([]float64, m+1)
+	y := make([]float64, m+1)
+	for i := 1; i <= m; i++ {
+		x[i] = 1 / float64(m)
+	}
+	est := 0.0
+	for iter := 0; iter < 5; iter++ {
+		copy(y, x)
+		C.glp_ftran(p.p.p, (*C.double)(unsafe.Pointer(&y[0])))
+		est = 0
+		for i := 1; i <= m; i++ {
+			est += math.Abs(y[i])
+			if y[i] >= 0 {
+				y[i] = 1
+			} else {
+				y[i] = -1
+			}
+		}
+		C.glp_btran(p.p.p, (*C.double)(unsafe.Pointer(&y[0])))
+		imax, zx := 1, 0.0
+		for i := 1; i <= m; i++ {
+			if math.Abs(y[i]) > math.Abs(y[imax]) {
+				imax = i
+			}
+			zx += y[i] * x[i]
+		}
+		if math.Abs(y[imax]) <= zx {
+			break
+		}
+		for i := 1; i <= m; i++ {
+			x[i] = 0
+		}
+		x[imax] = 1
+	}
+	return normB * est, nil
+}
+
+// PrimRTest performs the primal ratio test using an explicitly
+// specified column of the simplex table relative to the current
+// basis. ind[1]..ind[n] are numbers of basic variables (1..m for
+// auxiliary variables of the rows, m+1..m+n for structural variables
+// of the columns, see UnboundedRay) and val[1]..val[n] are the
+// corresponding influence coefficients (ind[0] and val[0] are
+// ignored). dir is +1 if the non-basic variable chosen to enter the
+// basis increases and -1 if it decreases, eps is an absolute
+// tolerance used to skip small coefficients. It returns the position
+// piv in ind such that ind[piv] is the basic variable which reaches
+// its bound first (i.e. should leave the basis), or 0 if the column
+// is unbounded. The current basic solution must be primal feasible.
+func (p *Prob) PrimRTest(ind []int32, val []float64, dir int, eps float64) int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(ind) != len(val) {
+		panic("len(ind) and len(val) should be equal")
+	}
+	indH := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
+	valH := (*reflect.SliceHeader)(unsafe.Pointer(&val))
+	return int(C.glp_prim_rtest(p.p.p, C.int(len(ind)-1), (*C.int)(unsafe.Pointer(indH.Data)), (*C.double)(unsafe.Pointer(valH.Data)), C.int(dir), C.double(eps)))
+}
+
+// DualRTest performs the dual ratio test using an explicitly
+// specified row of the simplex table relative to the current basis.
+// ind[1]..ind[n] are numbers of non-basic variables (numbered as in
+// PrimRTest) and val[1]..val[n] are the corresponding influence
+// coefficients (ind[0] and val[0] are ignored). dir is +1 if the basic
+// variable chosen to leave the basis increases and -1 if it decreases,
+// eps is an absolute tolerance used to skip small coefficients. It
+// returns the position piv in ind such that ind[piv] is the non-basic
+// variable whose reduced cost reaches zero first (i.e. should enter
+// the basis), or 0 if the row is dual unbounded. The current basic
+// solution must be dual feasible.
+func (p *Prob) DualRTest(ind []int32, val []float64, dir int, eps float64) int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(ind) != len(val) {
+		panic("len(ind) and len(val) should be equal")
+	}
+	indH := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
+	valH := (*reflect.SliceHeader)(unsafe.Pointer(&val))
+	return int(C.glp_dual_rtest(p.p.p, C.int(len(ind)-1), (*C.int)(unsafe.Pointer(indH.Data)), (*C.double)(unsafe.Pointer(valH.Data)), C.int(dir), C.double(eps)))
+}
+
 // Iocp represents MIP solver control parameters, a set of
 // parameters for Prob.Intopt(). Please use
 // NewIocp() to create Iocp structure which is properly initialized.
 type Iocp struct {
-	iocp C.glp_iocp
+	iocp       C.glp_iocp
+	nodeLim    int                               // limit on the number of subproblems (0 means no limit)
+	poolSize   int                               // maximum number of recorded MIP solutions
+	callback   func(*Tree)                       // user callback set with SetCallback()
+	initialSol []float64                         // initial solution set with SetInitialSolution()
+	heuristic  func([]float64) ([]float64, bool) // heuristic set with SetHeuristic()
+	output     io.Writer                         // GLPK terminal output (nil means stdout)
+	progress   chan<- Progress                   // used by Prob.IntoptProgress()
+	ctx        context.Context                   // used by Prob.IntoptProgress()
+}
+
+// needCallback checks whether Prob.Intopt() with these parameters
+// needs the branch-and-cut callback.
+func (p *Iocp) needCallback() bool {
+	return p.nodeLim > 0 || p.poolSize > 0 || p.callback != nil || p.progress != nil || p.initialSol != nil || p.heuristic != nil
 }
 
 // Presolve checks whether the optional MIP presolver is enabled.
@@ -854,20 +2970,274 @@ func (p *Iocp) SetMsgLev(lev MsgLev) {
 	p.iocp.msg_lev = C.int(lev)
 }
 
+// SetTmLim sets searching time limit in milliseconds (default: no
+// limit). If the limit is reached Prob.Intopt() returns glpk.ETMLIM.
+func (p *Iocp) SetTmLim(tmLim int) {
+	p.iocp.tm_lim = C.int(tmLim)
+}
+
+// SetNodeLim sets the limit on the number of subproblems (nodes of the
+// branch-and-bound tree) generated by the search (default: 0, i.e. no
+// limit). GLPK itself has no such limit so it is enforced by the glpk
+// package from the branch-and-cut callback. If the limit is exceeded
+// the search is terminated and Prob.Intopt() returns glpk.ESTOP.
+func (p *Iocp) SetNodeLim(nodeLim int) {
+	p.nodeLim = nodeLim
+}
+
+// SetDeterministic removes the time limit and sets the limit on the
+// number of subproblems to nodeLim (see SetNodeLim), so that the
+// search stops after the same number of subproblems (and with the
+// same result) no matter how fast the machine is.
+//
+// GLPK does not allow to set a random seed. The only randomized
+// components of the search are the feasibility pump and proximity
+// search heuristics (both disabled by default) and they always use
+// a pseudo-random generator with the same fixed seed, so the search is
+// reproducible given the same problem (including the order of rows and
+// columns) and the same parameters, as long as it is not stopped by
+// the time limit.
+func (p *Iocp) SetDeterministic(nodeLim int) {
+	p.iocp.tm_lim = math.MaxInt32
+	p.nodeLim = nodeLim
+}
+
+// SetSolutionPool sets the maximum number of integer feasible
+// solutions recorded during the search (default: 0, i.e. only the
+// final solution is available). The recorded solutions are returned by
+// Prob.MipSolutions(). GLPK reports only solutions better than the
+// current incumbent so the pool holds the last size incumbents found.
+// With the MIP presolver enabled the search is done on the presolved
+// problem and only the final solution is recorded.
+func (p *Iocp) SetSolutionPool(size int) {
+	p.poolSize = size
+}
+
+// SetOutput redirects the GLPK terminal output of Prob.Intopt() to
+// w (nil, the default, means stdout), see Smcp.SetOutput().
+func (p *Iocp) SetOutput(w io.Writer) {
+	p.output = w
+}
+
+// SetInitialSolution sets an integer feasible solution (e.g. found
+// by an earlier search or by a heuristic) used as the initial
+// incumbent of the search, which may speed up the search
+// considerably. x[1]..x[n] are values of the columns (x[0] is
+// ignored). Prob.Intopt() checks that the solution satisfies
+// integrality, bounds of the columns, and bounds of the rows, and
+// returns ErrInvalidSolution otherwise. A nil x (the default) means no
+// initial solution. The solution is not used if the MIP presolver is
+// enabled (as the search is done on the presolved problem).
+func (p *Iocp) SetInitialSolution(x []float64) {
+	p.initialSol = x
+}
+
+// SetCallback sets the branch-and-cut callback which is called by
+// Prob.Intopt() at various points of the search (see Tree.Reason()).
+// A nil callback (the default) disables it. A panic in the callback
+// terminates the search and is repeated after the search ends.
+func (p *Iocp) SetCallback(callback func(t *Tree)) {
+	p.callback = callback
+}
+
+// SetHeuristic sets a primal heuristic which is called by
+// Prob.Intopt() for each subproblem after its LP relaxation is solved
+// (when the callback is called with reason glpk.IHEUR), which is a
+// simpler alternative to calling Tree.HeurSol() from the callback
+// (see SetCallback). The heuristic gets the solution of the LP
+// relaxation of the current subproblem: relaxation[0] is the value of
+// the objective function and relaxation[1..n] are values of the
+// columns. If it finds an integer feasible solution it returns x with
+// x[1]..x[n] the values of the columns (x[0] is ignored) and true, and
+// the solution is provided to GLPK (which uses it as the new
+// incumbent if it is better than the current one). Solutions which do
+// not satisfy integrality or are not feasible for the current
+// subproblem (its column bounds and rows including the cuts) or have
+// a wrong length are silently rejected. A nil heuristic (the default)
+// disables it. It is not called if the MIP presolver is enabled (as
+// the search is done on the presolved problem). A panic in the
+// heuristic is handled as in the callback.
+func (p *Iocp) SetHeuristic(h func(relaxation []float64) ([]float64, bool)) {
+	p.heuristic = h
+}
+
 // NewIocp creates and initializes a new Iocp struct, which is used
-// by the branch-and-cut solver.
-func NewIocp() *Iocp {
+// by the branch-and-cut solver. The parameters have default values
+// modified by the given options (if any).
+func NewIocp(opts ...IocpOption) *Iocp {
 	p := new(Iocp)
 	C.glp_init_iocp(&p.iocp)
+	for _, opt := range opts {
+		opt.applyIocp(p)
+	}
 	return p
 }
 
+// Clone returns a copy of the parameters (including the callback and
+// the limits set with SetNodeLim and SetSolutionPool), so that it can
+// be modified without affecting p.
+func (p *Iocp) Clone() *Iocp {
+	q := *p
+	return &q
+}
+
+// setTermHook installs (or uninstalls) goTermHook as the GLPK term
+// hook (see withOutput).
+func setTermHook(on bool) {
+	if on {
+		C.set_term_hook(1)
+	} else {
+		C.set_term_hook(0)
+	}
+}
+
 // Intopt solves MIP problem with the branch-and-cut method.
 func (p *Prob) Intopt(params *Iocp) error {
 	if p.p.p == nil {
 		panic("Prob method called on a deleted problem")
 	}
-	err := OptError(C.glp_intopt(p.p.p, &params.iocp))
+	if params.initialSol != nil && !p.isFeasible(params.initialSol) {
+		return ErrInvalidSolution
+	}
+	iocp := params.iocp
+	var s *iosState
+	if params.needCallback() {
+		s = newIosState(params)
+		defer s.release()
+		C.set_ios_callback(&iocp, unsafe.Pointer(s.info))
+	}
+	p.p.pool = nil
+	var err OptError
+	withOutput(params.output, func() {
+		start := time.Now()
+		err = OptError(C.glp_intopt(p.p.p, &iocp))
+		p.p.solveTime = time.Since(start)
+		p.p.solveErr, p.p.solveMip, p.p.solved = err, true, true
+	})
+	if s != nil && s.panicked {
+		panic(s.panicVal)
+	}
+	if s != nil && params.poolSize > 0 {
+		p.p.pool = s.pool
+		p.recordFinalSolution(params.poolSize)
+	}
+	p.p.nodeCount, p.p.bestBound = 0, math.NaN()
+	if s != nil {
+		p.p.nodeCount, p.p.bestBound = s.nodeCount, s.bestBound
+	}
+	if st := SolStat(C.glp_mip_status(p.p.p)); st == OPT && (math.IsNaN(p.p.bestBound) || iocp.mip_gap == 0) {
+		p.p.bestBound = float64(C.glp_mip_obj_val(p.p.p))
+	}
+	if err != 0 {
+		return err
+	}
+	return nil
+}
+
+// Progress describes the state of the branch-and-cut search, see
+// Prob.IntoptProgress().
+type Progress struct {
+	Incumbent float64 // objective value of the best integer feasible solution found so far (NaN if not found yet)
+	BestBound float64 // best bound on the objective value over all active subproblems
+	Gap       float64 // relative MIP gap (+Inf if no integer feasible solution was found yet)
+}
+
+// IntoptProgress solves MIP problem with the branch-and-cut method
+// (just as Intopt does) in a separate goroutine. The progress of the
+// search is reported on the first returned channel whenever the
+// incumbent or the best bound changes. The progress events are not
+// buffered indefinitely, i.e. they are dropped when not received in
+// time, so the search never waits for the receiver. When the search
+// finishes (or is terminated) the progress channel is closed and the
+// result of Intopt is sent on the second channel (which is then also
+// closed). Typical usage is:
+//
+//	progress, errc := lp.IntoptProgress(ctx, iocp)
+//	for pr := range progress {
+//		fmt.Printf("incumbent %g, bound %g, gap %g\n", pr.Incumbent, pr.BestBound, pr.Gap)
+//	}
+//	if err := <-errc; err != nil {
+//		log.Fatal(err)
+//	}
+//
+// If ctx is cancelled (or its deadline passes) the search is
+// terminated at the next call of the branch-and-cut callback and
+// ctx.Err() is sent as the result (the best integer feasible solution
+// found so far, if any, is kept as for glpk.ESTOP). A panic in the
+// callback (see Iocp.SetCallback) is sent as a *PanicError. The
+// problem must not be used until the result is received from the
+// second channel.
+func (p *Prob) IntoptProgress(ctx context.Context, params *Iocp) (<-chan Progress, <-chan error) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	progress := make(chan Progress, 16)
+	errc := make(chan error, 1)
+	parm := *params
+	parm.progress = progress
+	parm.ctx = ctx
+	go func() {
+		defer close(errc)
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{r}
+				}
+			}()
+			err = p.Intopt(&parm)
+		}()
+		if err == ESTOP && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		close(progress)
+		errc <- err
+	}()
+	return progress, errc
+}
+
+// PanicError is sent by Prob.IntoptProgress() as the result of the
+// search if the branch-and-cut callback panicked.
+type PanicError struct {
+	Value interface{} // value passed to panic
+}
+
+func (e *PanicError) Error() string {
+	return "panic in branch-and-cut callback: " + fmt.Sprint(e.Value)
+}
+
+// IntFeas1 solves integer feasibility problem with a specialized
+// SAT-based solver which is often much faster than Intopt on such
+// problems. The problem must be a pure 0-1 problem, i.e. all columns
+// must be binary (or fixed), and all constraint coefficients and
+// bounds must be integer (not exceeding 2^31-1 in magnitude).
+//
+// If useBound is false the objective function is ignored. Otherwise
+// IntFeas1 searches for an integer feasible solution which has a
+// better objective value than objBound (which requires the objective
+// coefficients to be integer as well). The parameters are those of
+// glp_intfeas1(): objBound is the integer bound on the objective
+// function, and the objective row is always used if useBound is true
+// (there is no separate flag to use it without a bound).
+//
+// The solution (if found) is stored as a MIP solution, see MipStatus,
+// MipObjVal, and MipColVal. Returns nil if the solver finished (which
+// means that either a feasible solution was found, or MipStatus
+// returns glpk.NOFEAS) otherwise returns an error which is an instance
+// of OptError: glpk.EDATA if the problem data are not as described
+// above, glpk.ERANGE on integer overflow, or glpk.EFAIL if the solver
+// failed (or GLPK is too old to provide it).
+func (p *Prob) IntFeas1(useBound bool, objBound int) error {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	var useBoundC C.int
+	if useBound {
+		useBoundC = C.GLP_ON
+	} else {
+		useBoundC = C.GLP_OFF
+	}
+	err := OptError(C.intfeas1(p.p.p, useBoundC, C.int(objBound)))
 	if err != 0 {
 		return err
 	}
@@ -882,6 +3252,14 @@ func (p *Prob) MipStatus() SolStat {
 	return SolStat(C.glp_mip_status(p.p.p))
 }
 
+// MipRowVal returns value of the i-th row for MIP solution.
+func (p *Prob) MipRowVal(i int) float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_mip_row_val(p.p.p, C.int(i)))
+}
+
 // MipColVal returns value of the j-th column for MIP solution.
 func (p *Prob) MipColVal(i int) float64 {
 	if p.p.p == nil {
@@ -891,6 +3269,48 @@ func (p *Prob) MipColVal(i int) float64 {
 	return float64(val)
 }
 
+// MipNonzeroCols returns numbers of columns whose values in the MIP
+// solution are nonzero, i.e. |MipColVal(j)| > eps.
+func (p *Prob) MipNonzeroCols(eps float64) []int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	var cols []int
+	n := int(C.glp_get_num_cols(p.p.p))
+	for j := 1; j <= n; j++ {
+		if math.Abs(float64(C.glp_mip_col_val(p.p.p, C.int(j)))) > eps {
+			cols = append(cols, j)
+		}
+	}
+	return cols
+}
+
+// IntegralityGaps returns distances of the values of integer columns
+// in the optimal solution of the LP relaxation (see Relaxation) from
+// their values in the MIP solution (see MipColVal), which show where
+// the relaxation had to be cut off by branching: gaps[j] =
+// |ColPrim(j) - MipColVal(j)| for the relaxation if the j-th column is
+// integer (IV or BV) and gaps[j] = 0 otherwise, for j=1..n (gaps[0] is
+// ignored). The relaxation is solved on a copy of the problem with
+// Prob.Simplex(), so the cost is one extra simplex solve; the problem
+// itself is not modified. Returns nil if the relaxation has no optimal
+// solution.
+func (p *Prob) IntegralityGaps() []float64 {
+	q := p.Relaxation()
+	defer q.Delete()
+	if err := q.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil || q.Status() != OPT {
+		return nil
+	}
+	n := p.NumCols()
+	gaps := make([]float64, n+1)
+	for j := 1; j <= n; j++ {
+		if p.ColKind(j) != CV {
+			gaps[j] = math.Abs(q.ColPrim(j) - p.MipColVal(j))
+		}
+	}
+	return gaps
+}
+
 // MipObjVal returns value of the objective function for MIP solution.
 func (p *Prob) MipObjVal() float64 {
 	if p.p.p == nil {
@@ -900,6 +3320,211 @@ func (p *Prob) MipObjVal() float64 {
 	return float64(val)
 }
 
+// MipSolutions returns integer feasible solutions recorded by the
+// last call to Intopt if the solution pool was enabled with
+// Iocp.SetSolutionPool(). The solutions are ordered from the best one.
+// For each solution sol, sol[0] is the value of the objective function
+// and sol[1]..sol[n] are values of the columns.
+func (p *Prob) MipSolutions() [][]float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return p.p.pool
+}
+
+// recordFinalSolution adds the final MIP solution to the solution pool
+// unless it is already there (which is the case if the solution was
+// recorded by the callback).
+func (p *Prob) recordFinalSolution(poolSize int) {
+	if st := SolStat(C.glp_mip_status(p.p.p)); st != OPT && st != FEAS {
+		return
+	}
+	sol := mipSolution(p.p.p)
+	if len(p.p.pool) > 0 && reflect.DeepEqual(p.p.pool[0], sol) {
+		return
+	}
+	p.p.pool = append([][]float64{sol}, p.p.pool...)
+	if len(p.p.pool) > poolSize {
+		p.p.pool = p.p.pool[:poolSize]
+	}
+}
+
+// mipSolution returns the current MIP solution of prob in the format
+// used by Prob.MipSolutions().
+func mipSolution(prob *C.glp_prob) []float64 {
+	n := int(C.glp_get_num_cols(prob))
+	sol := make([]float64, n+1)
+	sol[0] = float64(C.glp_mip_obj_val(prob))
+	for j := 1; j <= n; j++ {
+		sol[j] = float64(C.glp_mip_col_val(prob, C.int(j)))
+	}
+	return sol
+}
+
+// MipBestBound returns the best bound on the objective value proven
+// by the last call to Intopt, i.e. the best bound over the subproblems
+// not yet solved when the search ended (or the optimal objective value
+// if the search finished with an optimal solution and mip gap
+// tolerance 0). This is useful when Intopt returns e.g. glpk.ETMLIM
+// with an integer feasible solution to know how good the solution is.
+// GLPK does not keep the bound after the search so it is recorded by
+// the glpk package from the branch-and-cut callback, which is
+// installed only if it is needed by the parameters of Intopt (a
+// callback, a solution pool, a node limit, an initial solution, or a
+// heuristic is set, see Iocp, or Prob.IntoptProgress() is used). It
+// returns NaN if no bound is known (e.g. Intopt was not called or the
+// callback was not installed and the search did not finish with an
+// optimal solution and mip gap tolerance 0).
+func (p *Prob) MipBestBound() float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return p.p.bestBound
+}
+
+// MipNodeCount returns the total number of subproblems (nodes of the
+// branch-and-bound tree) generated by the last call to Intopt. GLPK
+// does not keep this number after the search so it is recorded by the
+// glpk package from the branch-and-cut callback (see Tree.NodeCount)
+// at the last call of the callback. It is 0 if the search was not done
+// (e.g. the problem was solved by the MIP presolver) or the callback
+// was not installed (see MipBestBound).
+func (p *Prob) MipNodeCount() int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return p.p.nodeCount
+}
+
+// LastSolveTime returns the wall-clock time spent in GLPK by the last
+// call to Prob.Simplex(), Prob.Exact(), or Prob.Intopt() (also when it
+// returned an error), e.g. to compare solver parameters. It is
+// measured around the call of the GLPK solver (so it does not include
+// e.g. checking the initial solution given to Intopt) and 0 if the
+// problem has not been solved yet.
+func (p *Prob) LastSolveTime() time.Duration {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	return p.p.solveTime
+}
+
+// Outcome summarizes the result of the last solve, see
+// Prob.LastSolveOutcome().
+type Outcome int
+
+// Allowed values of type Outcome (result of the last solve).
+const (
+	NotSolved  Outcome = iota // no solver has been called
+	Optimal                   // optimal solution found
+	Feasible                  // search stopped by a limit with a feasible solution
+	Limited                   // search stopped by a limit without a feasible solution
+	Infeasible                // problem has no (primal) feasible solution
+	Unbounded                 // problem has unbounded solution
+	Failed                    // solver failed
+)
+
+// LastSolveOutcome returns the outcome of the last call to
+// Prob.Simplex(), Prob.Exact(), or Prob.Intopt() (including calls made
+// by other methods), derived from the error it returned and the status
+// of the basic solution (see Status) or of the MIP solution (see
+// MipStatus) for Intopt:
+//
+//	no error, status OPT                 Optimal
+//	no error, status FEAS                Feasible
+//	no error, status NOFEAS or INFEAS    Infeasible
+//	no error, status UNBND               Unbounded
+//	EITLIM, ETMLIM, EMIPGAP, ESTOP,
+//	EOBJLL, or EOBJUL, status FEAS/OPT   Feasible
+//	same errors, other status            Limited
+//	ENOPFS or ENOFEAS                    Infeasible
+//	ENODFS                               Unbounded (or infeasible)
+//	other errors                         Failed
+//
+// ESTOP is returned by Intopt also when the search was terminated by
+// the callback or the node limit (see Iocp.SetNodeLim) and EMIPGAP
+// when the MIP gap tolerance was reached. The statuses of the
+// solutions are read when LastSolveOutcome is called, so it should be
+// called before the problem is modified. Returns NotSolved if no
+// solver has been called.
+func (p *Prob) LastSolveOutcome() Outcome {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if !p.p.solved {
+		return NotSolved
+	}
+	st := p.Status()
+	if p.p.solveMip {
+		st = p.MipStatus()
+	}
+	switch p.p.solveErr {
+	case 0:
+		switch st {
+		case OPT:
+			return Optimal
+		case FEAS:
+			return Feasible
+		case NOFEAS, INFEAS:
+			return Infeasible
+		case UNBND:
+			return Unbounded
+		}
+		return Failed
+	case EITLIM, ETMLIM, EMIPGAP, ESTOP, EOBJLL, EOBJUL:
+		if st == FEAS || st == OPT {
+			return Feasible
+		}
+		return Limited
+	case ENOPFS, ENOFEAS:
+		return Infeasible
+	case ENODFS:
+		return Unbounded
+	}
+	return Failed
+}
+
+// FinalMipGap returns the relative MIP gap of the last call to Intopt,
+// i.e. |MipObjVal - best bound| / (|MipObjVal| + epsilon), where the
+// best bound is the best bound on the objective value over the
+// subproblems not yet solved when the search ended (see
+// MipBestBound). It is 0 if the search finished with an optimal
+// solution and mip gap tolerance 0, +Inf if no integer feasible
+// solution was found, and NaN if the best bound is not known (see
+// MipBestBound).
+func (p *Prob) FinalMipGap() float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if st := SolStat(C.glp_mip_status(p.p.p)); st != OPT && st != FEAS {
+		return math.Inf(1)
+	}
+	if math.IsNaN(p.p.bestBound) {
+		return math.NaN()
+	}
+	obj := float64(C.glp_mip_obj_val(p.p.p))
+	return math.Abs(obj-p.p.bestBound) / (math.Abs(obj) + C.DBL_EPSILON)
+}
+
+// ColValue returns value of the j-th column for the MIP solution if
+// the problem has integer columns (see NumInt) and MipStatus is
+// glpk.OPT or glpk.FEAS. Otherwise it returns value of the j-th
+// column for the basic solution (as ColPrim). This is useful in code
+// which solves either LP or MIP problems and needs not know which
+// one it was.
+func (p *Prob) ColValue(j int) float64 {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if C.glp_get_num_int(p.p.p) > 0 {
+		switch SolStat(C.glp_mip_status(p.p.p)) {
+		case OPT, FEAS:
+			return float64(C.glp_mip_col_val(p.p.p, C.int(j)))
+		}
+	}
+	return float64(C.glp_get_col_prim(p.p.p, C.int(j)))
+}
+
 // MPSFormat represents MPS file format: either fixed (ancient) or
 // free (modern) format.
 type MPSFormat int
@@ -923,7 +3548,7 @@ const (
 )
 
 // PathError is the error used by methods reading and writing MPS,
-// CPLEX LP, and GPLK LP/MIP formats.
+// CPLEX LP, GPLK LP/MIP, and DIMACS formats.
 type PathError struct {
 	Op      string // operation (either "read" or "write")
 	Path    string // name of the file on which the operation was performed
@@ -966,10 +3591,9 @@ func (p *Prob) WriteMPS(format MPSFormat, params *MPSCP, filename string) error
 	}
 	fname := C.CString(filename)
 	defer C.free(unsafe.Pointer(fname))
-	if C.glp_write_mps(p.p.p, C.int(format), parm, fname) != 0 {
-		return &PathError{"write", filename, "MPS writing error"}
-	}
-	return nil
+	return fileOp("write", filename, "MPS writing error", func() C.int {
+		return C.glp_write_mps(p.p.p, C.int(format), parm, fname)
+	})
 }
 
 // ReadMPS reads the problem instance from a file in MPS file format.
@@ -991,10 +3615,9 @@ func (p *Prob) ReadMPS(format MPSFormat, params *MPSCP, filename string) error {
 	}
 	fname := C.CString(filename)
 	defer C.free(unsafe.Pointer(fname))
-	if C.glp_read_mps(p.p.p, C.int(format), parm, fname) != 0 {
-		return &PathError{"read", filename, "MPS reading error"}
-	}
-	return nil
+	return fileOp("read", filename, "MPS reading error", func() C.int {
+		return C.glp_read_mps(p.p.p, C.int(format), parm, fname)
+	})
 }
 
 // CPXCP represent CPLEX LP format control parameters
@@ -1024,10 +3647,9 @@ func (p *Prob) WriteLP(params *CPXCP, filename string) error {
 	}
 	fname := C.CString(filename)
 	defer C.free(unsafe.Pointer(fname))
-	if C.glp_write_lp(p.p.p, parm, fname) != 0 {
-		return &PathError{"write", filename, "CPLEX LP writing error"}
-	}
-	return nil
+	return fileOp("write", filename, "CPLEX LP writing error", func() C.int {
+		return C.glp_write_lp(p.p.p, parm, fname)
+	})
 }
 
 // ReadLP reads the problem instance from a file in CPLEX LP file
@@ -1044,10 +3666,9 @@ func (p *Prob) ReadLP(params *CPXCP, filename string) error {
 	}
 	fname := C.CString(filename)
 	defer C.free(unsafe.Pointer(fname))
-	if C.glp_read_lp(p.p.p, parm, fname) != 0 {
-		return &PathError{"read", filename, "CPLEX LP reading error"}
-	}
-	return nil
+	return fileOp("read", filename, "CPLEX LP reading error", func() C.int {
+		return C.glp_read_lp(p.p.p, parm, fname)
+	})
 }
 
 // ProbRWFlags represents flags used for reading and writing of the
@@ -1064,10 +3685,9 @@ func (p *Prob) WriteProb(flags ProbRWFlags, filename string) error {
 	}
 	fname := C.CString(filename)
 	defer C.free(unsafe.Pointer(fname))
-	if C.glp_write_prob(p.p.p, C.int(flags), fname) != 0 {
-		return &PathError{"write", filename, "GLPK LP/MIP writing error"}
-	}
-	return nil
+	return fileOp("write", filename, "GLPK LP/MIP writing error", func() C.int {
+		return C.glp_write_prob(p.p.p, C.int(flags), fname)
+	})
 }
 
 // ReadProb reads the problem instance from a file in GLPK LP/MIP file
@@ -1079,8 +3699,139 @@ func (p *Prob) ReadProb(flags ProbRWFlags, filename string) error {
 	}
 	fname := C.CString(filename)
 	defer C.free(unsafe.Pointer(fname))
-	if C.glp_read_prob(p.p.p, C.int(flags), fname) != 0 {
-		return &PathError{"read", filename, "GLPK LP/MIP reading error"}
+	return fileOp("read", filename, "GLPK LP/MIP reading error", func() C.int {
+		return C.glp_read_prob(p.p.p, C.int(flags), fname)
+	})
+}
+
+// WriteFile writes the problem instance into a file in the format
+// chosen by the extension of filename: ".mps" for free MPS format (see
+// WriteMPS), ".lp" for CPLEX LP format (see WriteLP), and ".glpk" for
+// GLPK LP/MIP format (see WriteProb). The extension is case
+// insensitive. For other extensions it returns a PathError.
+func (p *Prob) WriteFile(filename string) error {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".mps":
+		return p.WriteMPS(MPS_FILE, nil, filename)
+	case ".lp":
+		return p.WriteLP(nil, filename)
+	case ".glpk":
+		return p.WriteProb(0, filename)
+	}
+	return &PathError{"write", filename, "unknown file format (expected extension .mps, .lp, or .glpk)"}
+}
+
+// ReadFile reads the problem instance from a file in the format chosen
+// by the extension of filename: ".mps" for MPS format (free MPS format
+// is tried first, then fixed MPS format), ".lp" for CPLEX LP format,
+// and ".glpk" for GLPK LP/MIP format (the extension is case
+// insensitive). For other extensions the format is guessed from the
+// content of the file. It returns a PathError if the format cannot be
+// determined or the file cannot be read in that format.
+func (p *Prob) ReadFile(filename string) error {
+	format := strings.ToLower(filepath.Ext(filename))
+	if format != ".mps" && format != ".lp" && format != ".glpk" {
+		var err error
+		if format, err = sniffFormat(filename); err != nil {
+			return err
+		}
+	}
+	return p.readFormat(format, filename)
+}
+
+// readFormat reads the problem instance from a file in the given
+// format (".mps", ".lp", or ".glpk", as in ReadFile).
+func (p *Prob) readFormat(format, filename string) error {
+	switch format {
+	case ".mps":
+		if err := p.ReadMPS(MPS_FILE, nil, filename); err == nil {
+			return nil
+		}
+		if err := p.ReadMPS(MPS_DECK, nil, filename); err != nil {
+			return &PathError{"read", filename, "MPS reading error (neither free nor fixed MPS format)"}
+		}
+		return nil
+	case ".lp":
+		return p.ReadLP(nil, filename)
+	default:
+		return p.ReadProb(0, filename)
 	}
-	return nil
+}
+
+// ErrUnknownFormat is returned by Prob.ReadAuto() if the problem
+// instance cannot be read in any of the supported formats.
+var ErrUnknownFormat = errors.New("unknown problem format (tried free MPS, fixed MPS, CPLEX LP, and GLPK LP/MIP formats)")
+
+// ReadAuto reads the problem instance from r in a format detected
+// from the content (as ReadFile does for files with unknown
+// extensions). As GLPK reads only files the content is first copied
+// to a temporary file. The guessed format is tried first and then the
+// remaining formats (MPS, CPLEX LP, and GLPK LP/MIP). Returns
+// ErrUnknownFormat if none of them succeeds, or an error returned by
+// r or while writing the temporary file.
+func (p *Prob) ReadAuto(r io.Reader) error {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	f, err := ioutil.TempFile("", "glpk-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	formats := []string{".mps", ".lp", ".glpk"}
+	if guess, err := sniffFormat(f.Name()); err == nil {
+		for k, format := range formats {
+			if format == guess {
+				copy(formats[1:k+1], formats[:k])
+				formats[0] = guess
+				break
+			}
+		}
+	}
+	for _, format := range formats {
+		if p.readFormat(format, f.Name()) == nil {
+			return nil
+		}
+	}
+	return ErrUnknownFormat
+}
+
+// sniffFormat guesses the format of a problem file from its first line
+// which is not empty nor a comment. It returns the extension (as
+// recognized by Prob.ReadFile) of the format.
+func sniffFormat(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", &PathError{"read", filename, err.Error()}
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "*") || strings.HasPrefix(fields[0], `\`) {
+			continue // MPS and CPLEX LP comments
+		}
+		switch strings.ToLower(fields[0]) {
+		case "c":
+			continue // GLPK LP/MIP comment
+		case "p":
+			return ".glpk", nil
+		case "name", "rows", "objsense":
+			return ".mps", nil
+		case "min", "minimize", "minimum", "max", "maximize", "maximum":
+			return ".lp", nil
+		}
+		break
+	}
+	if err := s.Err(); err != nil {
+		return "", &PathError{"read", filename, err.Error()}
+	}
+	return "", &PathError{"read", filename, "unknown file format"}
 }
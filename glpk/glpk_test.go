@@ -18,10 +18,13 @@
 package glpk
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"math"
 	"os"
+	"reflect"
+	"runtime"
 	"testing"
 )
 
@@ -32,6 +35,35 @@ func TestNewDelete(t *testing.T) {
 	lp.Delete() // second delete has no effect
 }
 
+func TestReset(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.SetProbName("problem")
+	lp.SetObjDir(MAX)
+	lp.AddRows(3)
+	lp.AddCols(2)
+	lp.Reset(true)
+	if n := lp.NumRows(); n != 0 {
+		t.Errorf("Got %d rows expected 0", n)
+	}
+	if n := lp.NumCols(); n != 0 {
+		t.Errorf("Got %d columns expected 0", n)
+	}
+	if s := lp.ProbName(); s != "problem" {
+		t.Errorf("keep=true but got name %#v instead of \"problem\"", s)
+	}
+	if lp.ObjDir() != MAX {
+		t.Errorf("Got %d instead of %d (MAX)", lp.ObjDir(), MAX)
+	}
+	lp.Reset(false)
+	if s := lp.ProbName(); s != "" {
+		t.Errorf("keep=false but got name %#v", s)
+	}
+	if lp.ObjDir() != MIN {
+		t.Errorf("Got %d instead of %d (MIN)", lp.ObjDir(), MIN)
+	}
+}
+
 func TestSetGetProbName(t *testing.T) {
 	lp := New()
 	name := "problem"
@@ -120,6 +152,152 @@ var bndsExpected = []struct {
 	{FX, 3.2, 3.2},
 }
 
+func TestSetRowColNames(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(2)
+	lp.AddCols(3)
+	rows := []string{"r1", "r2"}
+	cols := []string{"x", "y", "z"}
+	lp.SetRowNames(rows)
+	lp.SetColNames(cols)
+	for i, name := range rows {
+		if got := lp.RowName(i + 1); got != name {
+			t.Errorf("Got name %#v but %#v was set", got, name)
+		}
+	}
+	for j, name := range cols {
+		if got := lp.ColName(j + 1); got != name {
+			t.Errorf("Got name %#v but %#v was set", got, name)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for wrong number of names")
+		}
+	}()
+	lp.SetColNames(rows)
+}
+
+func TestAddColumn(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(3)
+	lp.AddCols(1)
+	ind := []int32{0, 1, 3}
+	val := []float64{0, 2.5, 3.5}
+	j := lp.AddColumn(4.0, ind, val, 0, math.Inf(1), IV)
+	if j != 2 {
+		t.Fatalf("expected column 2 but got %d", j)
+	}
+	if c := lp.ObjCoef(j); c != 4.0 {
+		t.Errorf("expected objective coefficient 4 but got %g", c)
+	}
+	if typ, lb := lp.ColType(j), lp.ColLB(j); typ != LO || lb != 0 {
+		t.Errorf("expected bounds (LO, 0) but got (%d, %g)", typ, lb)
+	}
+	if kind := lp.ColKind(j); kind != IV {
+		t.Errorf("expected kind IV but got %d", kind)
+	}
+	ind2, val2 := lp.MatCol(j)
+	if !CmpIndicesData(ind, val, ind2, val2) {
+		t.Errorf("Indices and values (%v, %v) does not match (%v, %v)", ind2, val2, ind, val)
+	}
+	j = lp.AddColumn(0, []int32{0}, []float64{0}, math.Inf(-1), math.Inf(1), CV)
+	if typ := lp.ColType(j); typ != FR {
+		t.Errorf("expected free column but got bounds type %d", typ)
+	}
+}
+
+func BenchmarkAddColumn(b *testing.B) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(100)
+	ind := []int32{0, 1, 10, 20, 30, 40, 50}
+	val := []float64{0, 1, 2, 3, 4, 5, 6}
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		lp.AddColumn(1.0, ind, val, 0, 10, CV)
+	}
+}
+
+func TestAddColumnByName(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	j, err := lp.AddColumnByName(5, map[string]float64{"p": 1, "r": 2}, 0, 10, CV)
+	if err != nil {
+		t.Fatalf("AddColumnByName error: %v", err)
+	}
+	if j != 4 {
+		t.Fatalf("expected column 4 but got %d", j)
+	}
+	if typ, lb, ub := lp.ColType(j), lp.ColLB(j), lp.ColUB(j); typ != DB || lb != 0 || ub != 10 {
+		t.Errorf("expected bounds (DB, 0, 10) but got (%d, %g, %g)", typ, lb, ub)
+	}
+	ind, val := lp.MatCol(j)
+	if !CmpIndicesData([]int32{0, 1, 3}, []float64{0, 1, 2}, ind, val) {
+		t.Errorf("unexpected column elements (%v, %v)", ind, val)
+	}
+	if _, err := lp.AddColumnByName(1, map[string]float64{"p": 1, "s": 1}, 0, 1, CV); err == nil {
+		t.Error("expected error for unknown row name")
+	}
+	if n := lp.NumCols(); n != 4 {
+		t.Errorf("expected 4 columns after failed AddColumnByName but got %d", n)
+	}
+}
+
+func BenchmarkAddColumnIncremental(b *testing.B) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(100)
+	ind := []int32{0, 1, 10, 20, 30, 40, 50}
+	val := []float64{0, 1, 2, 3, 4, 5, 6}
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		j := lp.AddCols(1)
+		lp.SetObjCoef(j, 1.0)
+		lp.SetMatCol(j, ind, val)
+		lp.SetColBnds(j, DB, 0, 10)
+	}
+}
+
+func TestNameIndex(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	lp.AddCols(1) // column without a name
+	rows, cols := lp.NameIndex()
+	if want := map[string]int{"p": 1, "q": 2, "r": 3}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("expected %v but got %v", want, rows)
+	}
+	if want := map[string]int{"x0": 1, "x1": 2, "x2": 3}; !reflect.DeepEqual(cols, want) {
+		t.Errorf("expected %v but got %v", want, cols)
+	}
+	if i := lp.FindRow("q"); i != 2 {
+		t.Errorf("expected row 2 but got %d", i)
+	}
+	lp.SetColName(4, "x3")
+	if j := lp.FindCol("x3"); j != 4 {
+		t.Errorf("expected column 4 but got %d", j)
+	}
+	if j := lp.FindCol("none"); j != 0 {
+		t.Errorf("expected 0 for unknown column but got %d", j)
+	}
+	lp.DeleteIndex()
+	if i := lp.FindRow("r"); i != 3 {
+		t.Errorf("expected row 3 but got %d", i)
+	}
+
+	dup := New()
+	defer dup.Delete()
+	dup.AddRows(3)
+	dup.SetRowName(1, "a")
+	dup.SetRowName(2, "b")
+	dup.SetRowName(3, "a")
+	if rows, _ := dup.NameIndex(); !reflect.DeepEqual(rows, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("expected the first of rows with duplicate names but got %v", rows)
+	}
+}
+
 func TestSetGetRowBnds(t *testing.T) {
 	lp := New()
 	lp.AddRows(1)
@@ -162,6 +340,161 @@ func TestSetGetColBnds(t *testing.T) {
 	lp.Delete()
 }
 
+func TestRowsCols(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	rows := lp.Rows()
+	if len(rows) != lp.NumRows() {
+		t.Fatalf("expected %d rows but got %d", lp.NumRows(), len(rows))
+	}
+	for k, r := range rows {
+		i := k + 1
+		want := RowInfo{i, lp.RowName(i), lp.RowType(i), lp.RowLB(i), lp.RowUB(i)}
+		if r != want {
+			t.Errorf("expected %v but got %v", want, r)
+		}
+	}
+	cols := lp.Cols()
+	if len(cols) != lp.NumCols() {
+		t.Fatalf("expected %d columns but got %d", lp.NumCols(), len(cols))
+	}
+	for k, c := range cols {
+		j := k + 1
+		want := ColInfo{j, lp.ColName(j), lp.ColKind(j), lp.ColType(j), lp.ColLB(j), lp.ColUB(j)}
+		if c != want {
+			t.Errorf("expected %v but got %v", want, c)
+		}
+	}
+	if cols[3].Kind != IV {
+		t.Errorf("expected integer 4th column but got kind %d", cols[3].Kind)
+	}
+}
+
+func TestColumn(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	c := lp.Column(4)
+	want := Column{Name: "x4", Kind: IV, Type: DB, LB: 2, UB: 3, Coef: 1}
+	check := func(c Column) {
+		if !CmpIndicesData(c.Ind, c.Val, []int32{0, 1, 3}, []float64{0, 10, -3.5}) {
+			t.Errorf("unexpected nonzero elements %v %v", c.Ind, c.Val)
+		}
+		c.Ind, c.Val = nil, nil
+		if !reflect.DeepEqual(c, want) {
+			t.Errorf("expected %v but got %v", want, c)
+		}
+	}
+	check(c)
+
+	// copy the column to another problem
+	lp2 := New()
+	defer lp2.Delete()
+	lp2.AddRows(lp.NumRows())
+	j := lp2.AddCols(1)
+	lp2.SetColName(j, c.Name)
+	lp2.SetColKind(j, c.Kind)
+	lp2.SetColBnds(j, c.Type, c.LB, c.UB)
+	lp2.SetObjCoef(j, c.Coef)
+	lp2.SetMatCol(j, c.Ind, c.Val)
+	check(lp2.Column(j))
+}
+
+func TestAppendColumns(t *testing.T) {
+	src := PrepareMipTestExample(t)
+	defer src.Delete()
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(3)
+	// the same rows in a different order
+	lp.SetRowName(1, "c3")
+	lp.SetRowName(2, "c1")
+	lp.SetRowName(3, "c2")
+	if err := lp.AppendColumns(src, []int{4, 2}); err != nil {
+		t.Fatalf("AppendColumns error: %v", err)
+	}
+	if n := lp.NumCols(); n != 2 {
+		t.Fatalf("expected 2 columns but got %d", n)
+	}
+	c := lp.Column(1)
+	if c.Name != "x4" || c.Kind != IV || c.Type != DB || c.LB != 2 || c.UB != 3 || c.Coef != 1 {
+		t.Errorf("unexpected column %v", c)
+	}
+	if !CmpIndicesData(c.Ind, c.Val, []int32{0, 2, 1}, []float64{0, 10, -3.5}) {
+		t.Errorf("unexpected nonzero elements %v %v", c.Ind, c.Val)
+	}
+	if name := lp.ColName(2); name != "x2" {
+		t.Errorf("expected x2 but got %q", name)
+	}
+
+	lp.SetRowName(3, "other")
+	if err := lp.AppendColumns(src, []int{1}); err == nil {
+		t.Error("expected an error for a missing row")
+	}
+	if n := lp.NumCols(); n != 2 {
+		t.Errorf("expected the problem to be unmodified but got %d columns", n)
+	}
+}
+
+func TestBasisCondition(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(2)
+	lp.SetRowBnds(1, UP, 0, 1)
+	lp.SetRowBnds(2, UP, 0, 1)
+	lp.AddCols(2)
+	lp.SetMatRow(1, []int32{0, 1}, []float64{0, 1})
+	lp.SetMatRow(2, []int32{0, 2}, []float64{0, 1e-3})
+	if _, err := lp.BasisCondition(); err != ErrNoFactorization {
+		t.Errorf("expected ErrNoFactorization but got %v", err)
+	}
+	// all rows basic: B = I
+	if err := lp.Factorize(); err != nil {
+		t.Fatalf("Factorize error: %v", err)
+	}
+	cond, err := lp.BasisCondition()
+	if err != nil {
+		t.Fatalf("BasisCondition error: %v", err)
+	}
+	CheckClose(t, cond, 1)
+	// all columns basic: B = -A
+	for k := 1; k <= 2; k++ {
+		lp.SetRowStat(k, NU)
+		lp.SetColStat(k, BS)
+	}
+	if err := lp.Factorize(); err != nil {
+		t.Fatalf("Factorize error: %v", err)
+	}
+	if cond, err = lp.BasisCondition(); err != nil {
+		t.Fatalf("BasisCondition error: %v", err)
+	}
+	if math.Abs(cond-1000) > 1e-6 {
+		t.Errorf("expected condition number 1000 but got %g", cond)
+	}
+}
+
+func TestSetMatRows(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	lp.SetMatRows([]int{3, 1}, [][]int32{{0, 1, 3}, nil}, [][]float64{{0, 2.5, 4}, nil})
+	ind, val := lp.MatRow(3)
+	if !CmpIndicesData(ind, val, []int32{0, 1, 3}, []float64{0, 2.5, 4}) {
+		t.Errorf("unexpected row 3: %v %v", ind, val)
+	}
+	if ind, _ := lp.MatRow(1); len(ind) != 1 {
+		t.Errorf("expected empty row 1 but got %v", ind)
+	}
+	ind, val = lp.MatRow(2)
+	if !CmpIndicesData(ind, val, []int32{0, 1, 2, 3}, []float64{0, 10, 4, 5}) {
+		t.Errorf("unexpected row 2: %v %v", ind, val)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for slices of different lengths")
+		}
+	}()
+	lp.SetMatRows([]int{1}, nil, nil)
+}
+
 func TestSetGetRowStat(t *testing.T) {
 	lp := New()
 	lp.AddRows(1)
@@ -262,6 +595,111 @@ func TestSetGetMatix(t *testing.T) {
 	lp.Delete()
 }
 
+func TestCoef(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(2)
+	lp.AddCols(3)
+	ia := []int32{0, 1, 1, 1, 2}
+	ja := []int32{0, 1, 2, 3, 2}
+	ar := []float64{0, 1.5, 2.5, 3.5, 4.5}
+	lp.LoadMatrix(ia, ja, ar)
+	for k := 1; k < len(ia); k++ {
+		if v := lp.Coef(int(ia[k]), int(ja[k])); v != ar[k] {
+			t.Errorf("expected matrix[%d, %d] = %g but got %g", ia[k], ja[k], ar[k], v)
+		}
+	}
+	if v := lp.Coef(2, 3); v != 0 {
+		t.Errorf("expected 0 for absent element but got %g", v)
+	}
+}
+
+func TestSetCoef(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(2)
+	lp.AddCols(3)
+	lp.SetCoef(1, 2, 5.0) // insert
+	lp.SetCoef(1, 3, 6.0) // insert
+	lp.SetCoef(1, 2, 7.0) // update
+	lp.SetCoef(2, 1, 0.0) // remove absent
+	if v := lp.Coef(1, 2); v != 7.0 {
+		t.Errorf("expected 7 but got %g", v)
+	}
+	if v := lp.Coef(1, 3); v != 6.0 {
+		t.Errorf("expected 6 but got %g", v)
+	}
+	lp.SetCoef(1, 2, 0.0) // remove
+	if n := lp.NumNz(); n != 1 {
+		t.Errorf("expected 1 nonzero element but got %d", n)
+	}
+	if v := lp.Coef(1, 2); v != 0 {
+		t.Errorf("expected 0 but got %g", v)
+	}
+}
+
+func TestForEachNonzero(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	type elem struct{ i, j int }
+	got := make(map[elem]float64)
+	lastJ := 0
+	lp.ForEachNonzero(func(i, j int, v float64) {
+		if j < lastJ {
+			t.Errorf("column %d visited after column %d", j, lastJ)
+		}
+		lastJ = j
+		got[elem{i, j}] = v
+	})
+	want := map[elem]float64{
+		{1, 1}: 1.0, {1, 2}: 1.0, {1, 3}: 1.0,
+		{2, 1}: 10.0, {2, 2}: 4.0, {2, 3}: 5.0,
+		{3, 1}: 2.0, {3, 2}: 2.0, {3, 3}: 6.0,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v but got %v", want, got)
+	}
+}
+
+func TestEmptyRowsCols(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(3)
+	lp.AddCols(4)
+	if rows := lp.EmptyRows(); !reflect.DeepEqual(rows, []int{1, 2, 3}) {
+		t.Errorf("expected empty rows [1 2 3] but got %v", rows)
+	}
+	ia := []int32{0, 1, 1, 3}
+	ja := []int32{0, 1, 4, 4}
+	ar := []float64{0, 1.0, 2.0, 3.0}
+	lp.LoadMatrix(ia, ja, ar)
+	if n := lp.NumNz(); n != 3 {
+		t.Errorf("expected 3 nonzero elements but got %d", n)
+	}
+	if rows := lp.EmptyRows(); !reflect.DeepEqual(rows, []int{2}) {
+		t.Errorf("expected empty rows [2] but got %v", rows)
+	}
+	if cols := lp.EmptyCols(); !reflect.DeepEqual(cols, []int{2, 3}) {
+		t.Errorf("expected empty columns [2 3] but got %v", cols)
+	}
+}
+
+func TestClearRowCol(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	lp.ClearRow(2)
+	if ind, _ := lp.MatRow(2); len(ind) != 1 {
+		t.Errorf("expected empty row but got %d elements", len(ind)-1)
+	}
+	lp.ClearCol(3)
+	if ind, _ := lp.MatCol(3); len(ind) != 1 {
+		t.Errorf("expected empty column but got %d elements", len(ind)-1)
+	}
+	if n := lp.NumNz(); n != 4 {
+		t.Errorf("expected 4 nonzero elements but got %d", n)
+	}
+}
+
 func TestCopy(t *testing.T) {
 	lp := New()
 	lp.AddRows(4)
@@ -303,6 +741,24 @@ func TestSetGetObjCoef(t *testing.T) {
 	}
 }
 
+func TestSetObjCoefs(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddCols(3)
+	lp.SetObjCoefs([]int{0, 3, 1}, []float64{5.0, 7.5, 2.5})
+	for j, want := range []float64{5.0, 2.5, 0, 7.5} {
+		if c := lp.ObjCoef(j); c != want {
+			t.Errorf("expected coefficient %g of column %d but got %g", want, j, c)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for column number out of range")
+		}
+	}()
+	lp.SetObjCoefs([]int{4}, []float64{1.0})
+}
+
 func CheckClose(t *testing.T, v1, v2 float64) {
 	if math.Abs(v1-v2) > 1e-10 {
 		t.Errorf("values %g and %g differ by %g", v1, v2, v1-v2)
@@ -397,101 +853,615 @@ func TestExample(t *testing.T) {
 	lp2.Delete()
 }
 
-func TestReadWriteMPS(t *testing.T) {
+func TestSimplexWithExactFallback(t *testing.T) {
 	lp := PrepareTestExample(t)
-	f1, err := ioutil.TempFile("", "glpk-test-")
-	if err != nil {
-		t.Fatal(err)
+	defer lp.Delete()
+	if err := lp.SimplexWithExactFallback(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("SimplexWithExactFallback error: %v", err)
 	}
-	f1.Close()
-	defer os.Remove(f1.Name())
-	f2, err := ioutil.TempFile("", "glpk-test-")
-	if err != nil {
-		t.Fatal(err)
+	CheckSolution(t, lp)
+	if err := lp.SimplexWithExactFallback(nil); err != nil {
+		t.Fatalf("SimplexWithExactFallback error: %v", err)
 	}
-	f2.Close()
-	defer os.Remove(f2.Name())
-	err = lp.WriteMPS(MPS_DECK, nil, f1.Name())
-	if err != nil {
-		t.Error(err)
+	CheckSolution(t, lp)
+}
+
+func TestLoadProblem(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(5) // erased by LoadProblem
+	lp.LoadProblem(ProblemSpec{
+		Name:    "sample",
+		ObjName: "Z",
+		Dir:     MAX,
+		Rows: []RowSpec{
+			{Name: "p", Type: UP, UB: 100.0},
+			{Name: "q", Type: UP, UB: 600.0},
+			{Name: "r", Type: UP, UB: 300.0},
+		},
+		Cols: []ColSpec{
+			{Name: "x0", Type: LO, Coef: 10.0},
+			{Name: "x1", Type: LO, Coef: 6.0},
+			{Name: "x2", Type: LO, Coef: 4.0},
+		},
+		Ia: []int32{0, 1, 1, 1, 2, 2, 2, 3, 3, 3},
+		Ja: []int32{0, 1, 2, 3, 1, 2, 3, 1, 2, 3},
+		Ar: []float64{0, 1.0, 1.0, 1.0, 10.0, 4.0, 5.0, 2.0, 2.0, 6.0},
+	})
+	if n := lp.NumRows(); n != 3 {
+		t.Fatalf("expected 3 rows but got %d", n)
 	}
-	err = lp.WriteMPS(MPS_FILE, NewMPSCP(), f2.Name())
-	if err != nil {
-		t.Error(err)
+	if name := lp.ProbName(); name != "sample" {
+		t.Errorf("expected problem name %q but got %q", "sample", name)
 	}
-	lp.Delete()
+	if name := lp.RowName(2); name != "q" {
+		t.Errorf("expected row name %q but got %q", "q", name)
+	}
+	if name := lp.ColName(3); name != "x2" {
+		t.Errorf("expected column name %q but got %q", "x2", name)
+	}
+	CheckSimplexSolution(t, lp)
+}
 
-	lp1 := New()
-	defer lp1.Delete()
-	err = lp1.ReadMPS(MPS_DECK, NewMPSCP(), f1.Name())
-	if err != nil {
-		t.Error(err)
-	} else {
-		lp1.SetObjDir(MAX)
-		CheckSimplexSolution(t, lp1)
+// PrepareBenchmarkSpec returns a problem with m rows, n columns and
+// approximately 10% density of the constraint matrix.
+func PrepareBenchmarkSpec(m, n int) ProblemSpec {
+	spec := ProblemSpec{
+		Dir:  MAX,
+		Rows: make([]RowSpec, m),
+		Cols: make([]ColSpec, n),
+		Ia:   []int32{0},
+		Ja:   []int32{0},
+		Ar:   []float64{0},
+	}
+	for i := range spec.Rows {
+		spec.Rows[i] = RowSpec{Name: fmt.Sprintf("r%d", i+1), Type: UP, UB: 100}
 	}
+	for j := range spec.Cols {
+		spec.Cols[j] = ColSpec{Name: fmt.Sprintf("c%d", j+1), Type: DB, UB: 10, Coef: float64(j%7 + 1)}
+	}
+	for i := 1; i <= m; i++ {
+		for j := i % 10; j < n; j += 10 {
+			spec.Ia = append(spec.Ia, int32(i))
+			spec.Ja = append(spec.Ja, int32(j+1))
+			spec.Ar = append(spec.Ar, float64((i+j)%5+1))
+		}
+	}
+	return spec
+}
 
-	lp2 := New()
-	defer lp2.Delete()
-	err = lp2.ReadMPS(MPS_FILE, nil, f2.Name())
-	if err != nil {
-		t.Error(err)
-	} else {
-		lp2.SetObjDir(MAX)
-		CheckSimplexSolution(t, lp2)
+func BenchmarkLoadProblem(b *testing.B) {
+	spec := PrepareBenchmarkSpec(200, 2000)
+	lp := New()
+	defer lp.Delete()
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		lp.LoadProblem(spec)
 	}
 }
 
-func TestReadWriteLP(t *testing.T) {
-	CheckReadWriteLP(t, nil)
-	CheckReadWriteLP(t, NewCPXCP())
+func BenchmarkLoadProblemIncremental(b *testing.B) {
+	spec := PrepareBenchmarkSpec(200, 2000)
+	lp := New()
+	defer lp.Delete()
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		lp.Erase()
+		lp.SetObjDir(spec.Dir)
+		lp.AddRows(len(spec.Rows))
+		for i, r := range spec.Rows {
+			lp.SetRowName(i+1, r.Name)
+			lp.SetRowBnds(i+1, r.Type, r.LB, r.UB)
+		}
+		lp.AddCols(len(spec.Cols))
+		for j, c := range spec.Cols {
+			lp.SetColName(j+1, c.Name)
+			lp.SetColBnds(j+1, c.Type, c.LB, c.UB)
+			lp.SetObjCoef(j+1, c.Coef)
+		}
+		lp.LoadMatrix(spec.Ia, spec.Ja, spec.Ar)
+	}
 }
 
-func CheckReadWriteLP(t *testing.T, cpxcp *CPXCP) {
+func TestScaleProb(t *testing.T) {
 	lp := PrepareTestExample(t)
-	f, err := ioutil.TempFile("", "glpk-test-")
-	if err != nil {
-		t.Fatal(err)
+	defer lp.Delete()
+	lp.ScaleProb(SF_GM | SF_EQ | SF_2N)
+	scaled := false
+	for i := 1; i <= lp.NumRows(); i++ {
+		if lp.RowScale(i) != 1 {
+			scaled = true
+		}
 	}
-	f.Close()
-	defer os.Remove(f.Name())
-	err = lp.WriteLP(cpxcp, f.Name())
-	if err != nil {
-		t.Error(err)
+	for j := 1; j <= lp.NumCols(); j++ {
+		if lp.ColScale(j) != 1 {
+			scaled = true
+		}
 	}
-	lp.Delete()
-
-	lp1 := New()
-	defer lp1.Delete()
-	err = lp1.ReadLP(cpxcp, f.Name())
-	if err != nil {
-		t.Fatal(err)
+	if !scaled {
+		t.Error("expected some scale factors other than 1")
+	}
+	ind, val := lp.MatRow(2)
+	if !CmpIndicesData([]int32{0, 1, 2, 3}, []float64{0, 10.0, 4.0, 5.0}, ind, val) {
+		t.Errorf("expected unscaled row but got (%v, %v)", ind, val)
+	}
+	CheckSimplexSolution(t, lp) // checks ObjVal in the original units
+	lp.UnscaleProb()
+	for j := 1; j <= lp.NumCols(); j++ {
+		CheckClose(t, lp.ColScale(j), 1)
 	}
-	CheckSimplexSolution(t, lp1)
 }
 
-func TestReadWriteProb(t *testing.T) {
+func TestUnboundedRay(t *testing.T) {
 	lp := PrepareTestExample(t)
-	f, err := ioutil.TempFile("", "glpk-test-")
-	if err != nil {
-		t.Fatal(err)
-	}
-	f.Close()
-	defer os.Remove(f.Name())
-	err = lp.WriteProb(0, f.Name())
-	if err != nil {
-		t.Error(err)
+	CheckSimplexSolution(t, lp)
+	if k := lp.UnboundedRay(); k != 0 {
+		t.Errorf("expected 0 for bounded solution but got %d", k)
 	}
 	lp.Delete()
 
-	lp1 := New()
-	defer lp1.Delete()
-	err = lp1.ReadProb(0, f.Name())
-	if err != nil {
-		t.Fatal(err)
+	// maximize x subject to x - y <= 1, x, y >= 0
+	lp = New()
+	defer lp.Delete()
+	lp.SetObjDir(MAX)
+	lp.AddRows(1)
+	lp.SetRowBnds(1, UP, 0, 1)
+	lp.AddCols(2)
+	lp.SetColBnds(1, LO, 0, 0)
+	lp.SetColBnds(2, LO, 0, 0)
+	lp.SetObjCoef(1, 1)
+	lp.SetMatRow(1, []int32{0, 1, 2}, []float64{0, 1, -1})
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Fatalf("Simplex error: %v", err)
 	}
-	CheckSimplexSolution(t, lp1)
-}
+	if st := lp.Status(); st != UNBND {
+		t.Fatalf("expected status %d but got %d", UNBND, st)
+	}
+	if k := lp.UnboundedRay(); k < 1 || k > lp.NumRows()+lp.NumCols() {
+		t.Errorf("expected variable number in [1, 3] but got %d", k)
+	}
+}
+
+func TestSplitCombineK(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(3)
+	lp.AddCols(2)
+	for k := 1; k <= 5; k++ {
+		isRow, idx := lp.SplitK(k)
+		if isRow != (k <= 3) {
+			t.Errorf("expected isRow = %v for k = %d", k <= 3, k)
+		}
+		if k2 := lp.CombineK(isRow, idx); k2 != k {
+			t.Errorf("expected %d but got %d", k, k2)
+		}
+	}
+	if isRow, idx := lp.SplitK(5); isRow || idx != 2 {
+		t.Errorf("expected column 2 but got (%v, %d)", isRow, idx)
+	}
+}
+
+func TestSimplexScaled(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if err := lp.SimplexScaled(NewSmcp(WithMsgLev(MSG_ERR)), 0); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	CheckSolution(t, lp)
+	for j := 1; j <= lp.NumCols(); j++ {
+		CheckClose(t, lp.ColScale(j), 1)
+	}
+}
+
+func TestFindFeasible(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	coefs := lp.ObjCoefs()
+	if len(coefs) != 4 {
+		t.Fatalf("expected 4 coefficients but got %d", len(coefs))
+	}
+	smcp := NewSmcp(WithMsgLev(MSG_ERR))
+	ok, err := lp.FindFeasible(smcp)
+	if err != nil {
+		t.Fatalf("FindFeasible error: %v", err)
+	}
+	if !ok {
+		t.Error("expected the problem to be feasible")
+	}
+	for j, c := range lp.ObjCoefs() {
+		CheckClose(t, c, coefs[j])
+	}
+	lp.SetColBnds(1, LO, 70, 0)
+	for _, presolve := range []bool{false, true} {
+		smcp.SetPresolve(presolve)
+		ok, err = lp.FindFeasible(smcp)
+		if err != nil {
+			t.Fatalf("FindFeasible error: %v", err)
+		}
+		if ok {
+			t.Errorf("expected the problem to be infeasible (presolve: %v)", presolve)
+		}
+	}
+	CheckClose(t, lp.ObjCoef(1), 10)
+}
+
+func TestSolveWithFixed(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	smcp := NewSmcp(WithMsgLev(MSG_ERR))
+	if err := lp.SolveWithFixed(map[int]float64{2: 0}, false, smcp); err != nil {
+		t.Fatalf("SolveWithFixed error: %v", err)
+	}
+	CheckClose(t, lp.ObjVal(), 600)
+	CheckClose(t, lp.ColPrim(1), 60)
+	if typ := lp.ColType(2); typ != LO {
+		t.Errorf("expected restored bounds type LO but got %d", typ)
+	}
+	if err := lp.SolveWithFixed(map[int]float64{2: 0}, true, smcp); err != nil {
+		t.Fatalf("SolveWithFixed error: %v", err)
+	}
+	if typ, v := lp.ColType(2), lp.ColLB(2); typ != FX || v != 0 {
+		t.Errorf("expected column fixed at 0 but got (%d, %g)", typ, v)
+	}
+	CheckClose(t, lp.ObjVal(), 600)
+}
+
+func TestReSolveDual(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	CheckSimplexSolution(t, lp)
+	smcp := NewSmcp(WithMsgLev(MSG_ERR), WithPresolve(true))
+	for _, ub := range []float64{90, 80} {
+		lp.SetRowBnds(1, UP, 0, ub)
+		if err := lp.ReSolveDual(smcp); err != nil {
+			t.Fatalf("ReSolveDual error: %v", err)
+		}
+		if s := lp.Status(); s != OPT {
+			t.Fatalf("expected optimal solution but got %v", s)
+		}
+		// x0 + x1 = ub and 10 x0 + 4 x1 = 600 (both rows active)
+		x0 := (600 - 4*ub) / 6
+		CheckClose(t, lp.ColPrim(1), x0)
+		CheckClose(t, lp.ColPrim(2), ub-x0)
+	}
+	if !smcp.Presolve() {
+		t.Error("expected ReSolveDual not to modify parameters")
+	}
+}
+
+func TestBasis(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	CheckSimplexSolution(t, lp)
+	rows, cols := lp.Basis()
+	if !reflect.DeepEqual(rows, []int{3}) || !reflect.DeepEqual(cols, []int{1, 2}) {
+		t.Errorf("expected rows [3] and columns [1 2] but got %v and %v", rows, cols)
+	}
+}
+
+func TestHasBasis(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if lp.HasBasis() {
+		t.Error("expected no basis before solving")
+	}
+	CheckSimplexSolution(t, lp)
+	if !lp.HasBasis() {
+		t.Error("expected a basis after solving")
+	}
+	lp.SetColBnds(1, LO, 70, 0)
+	smcp := NewSmcp(WithMsgLev(MSG_ERR), WithPresolve(true))
+	if err := lp.Simplex(smcp); err != ENOPFS {
+		t.Fatalf("expected %v but got %v", ENOPFS, err)
+	}
+	if lp.HasBasis() {
+		t.Error("expected no basis after the presolver found the problem infeasible")
+	}
+}
+
+func TestSolveObjectiveSweep(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	objs := [][]float64{
+		{0, 10, 6, 4},
+		{1, 10, 6, 4},
+		{0, 1, 0, 0},   // x0 <= 60 (from row q)
+		{0, 0, 0, 1e4}, // x2 <= 50 (from row r)
+	}
+	vals, err := lp.SolveObjectiveSweep(objs, NewSmcp(WithMsgLev(MSG_ERR), WithPresolve(true)))
+	if err != nil {
+		t.Fatalf("SolveObjectiveSweep error: %v", err)
+	}
+	expected := []float64{733 + 1.0/3, 734 + 1.0/3, 60, 5e5}
+	if len(vals) != len(expected) {
+		t.Fatalf("expected %d values but got %d", len(expected), len(vals))
+	}
+	for k := range expected {
+		if math.Abs(vals[k]-expected[k]) > 1e-9*math.Max(1, expected[k]) {
+			t.Errorf("expected %g but got %g", expected[k], vals[k])
+		}
+	}
+	CheckClose(t, lp.ObjCoef(1), 10)
+	CheckClose(t, lp.ObjCoef(3), 4)
+
+	lp.SetColBnds(1, FR, 0, 0)
+	vals, err = lp.SolveObjectiveSweep([][]float64{{0, 1, 0, 0}, {0, -1, 0, 0}}, NewSmcp(WithMsgLev(MSG_ERR)))
+	if err != ErrNotOptimal {
+		t.Errorf("expected ErrNotOptimal but got %v", err)
+	}
+	if len(vals) != 1 {
+		t.Errorf("expected 1 value before the error but got %v", vals)
+	}
+}
+
+func TestFactorize(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if lp.BasisFactorizationValid() {
+		t.Error("expected no factorization before solving")
+	}
+	CheckSimplexSolution(t, lp)
+	lp.SetRowStat(3, NL) // row 3 is basic in the optimal basis
+	if lp.BasisFactorizationValid() {
+		t.Error("expected invalid factorization after changing the basis")
+	}
+	if err := lp.Factorize(); err != EBADB {
+		t.Errorf("expected %v but got %v", EBADB, err)
+	}
+	for i := 1; i <= lp.NumRows(); i++ {
+		lp.SetRowStat(i, BS)
+	}
+	for j := 1; j <= lp.NumCols(); j++ {
+		lp.SetColStat(j, NL)
+	}
+	if err := lp.Factorize(); err != nil {
+		t.Errorf("Factorize error: %v", err)
+	}
+	if !lp.BasisFactorizationValid() {
+		t.Error("expected valid factorization after Factorize")
+	}
+	if lp.BasisFactorizationUpdated() {
+		t.Error("expected not updated factorization after Factorize")
+	}
+}
+
+func TestAdvBasis(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	lp.AdvBasis()
+	if err := lp.Factorize(); err != nil {
+		t.Errorf("Factorize error: %v", err)
+	}
+	CheckSimplexSolution(t, lp)
+}
+
+func TestGuessBasis(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	x := []float64{0, 100.0 / 3, 200.0 / 3, 0}
+	if err := lp.GuessBasis(x); err != nil {
+		t.Fatalf("GuessBasis error: %v", err)
+	}
+	for i, expected := range []VarStat{0, NU, NU, BS} {
+		if i > 0 && lp.RowStat(i) != expected {
+			t.Errorf("expected status %d of row %d but got %d", expected, i, lp.RowStat(i))
+		}
+	}
+	for j, expected := range []VarStat{0, BS, BS, NL} {
+		if j > 0 && lp.ColStat(j) != expected {
+			t.Errorf("expected status %d of column %d but got %d", expected, j, lp.ColStat(j))
+		}
+	}
+	CheckSimplexSolution(t, lp)
+
+	// too many basic variables for an interior point
+	if err := lp.GuessBasis([]float64{0, 1, 1, 1}); err != nil {
+		t.Fatalf("GuessBasis error: %v", err)
+	}
+	CheckSimplexSolution(t, lp)
+
+	stats := []VarStat{0, NL, NL, NL}
+	lp.SetColStats(stats)
+	for j := 1; j <= 3; j++ {
+		if s := lp.ColStat(j); s != NL {
+			t.Errorf("expected status NL of column %d but got %d", j, s)
+		}
+	}
+}
+
+func TestNumEqualities(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	if n := lp.NumEqualities(); n != 1 {
+		t.Errorf("expected 1 equality but got %d", n)
+	}
+}
+
+func TestPresolvedStats(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if m, n, nz := lp.Stats(); m != 3 || n != 3 || nz != 9 {
+		t.Errorf("expected stats (3, 3, 9) but got (%d, %d, %d)", m, n, nz)
+	}
+	m, n, nz, err := lp.PresolvedStats()
+	if err == EFAIL {
+		t.Skip("GLPK too old to provide presolver API")
+	}
+	if err != nil {
+		t.Fatalf("PresolvedStats error: %v", err)
+	}
+	if m > 3 || n > 3 || nz > 9 {
+		t.Errorf("expected reduced stats but got (%d, %d, %d)", m, n, nz)
+	}
+	if m, n, nz := lp.Stats(); m != 3 || n != 3 || nz != 9 {
+		t.Errorf("expected unmodified stats (3, 3, 9) but got (%d, %d, %d)", m, n, nz)
+	}
+
+	// an empty row with bounds excluding zero is infeasible
+	i := lp.AddRows(1)
+	lp.SetRowBnds(i, LO, 1, 0)
+	if _, _, _, err := lp.PresolvedStats(); err != ENOPFS {
+		t.Errorf("expected %v but got %v", ENOPFS, err)
+	}
+
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_OFF)
+	smcp.SetPresolve(true)
+	if !smcp.Presolve() {
+		t.Error("expected presolver to be enabled")
+	}
+	if err := lp.Simplex(smcp); err != ENOPFS {
+		t.Errorf("expected %v but got %v", ENOPFS, err)
+	}
+}
+
+func TestIsBasic(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	CheckSimplexSolution(t, lp)
+	// x0 = 33.33, x1 = 66.67, x2 = 0; rows p and q are active
+	for i, basic := range []bool{false, false, true} {
+		if lp.RowIsBasic(i+1) != basic {
+			t.Errorf("expected RowIsBasic(%d) = %v", i+1, basic)
+		}
+	}
+	for j, basic := range []bool{true, true, false} {
+		if lp.ColIsBasic(j+1) != basic {
+			t.Errorf("expected ColIsBasic(%d) = %v", j+1, basic)
+		}
+	}
+}
+
+func TestRatioTest(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	CheckSimplexSolution(t, lp)
+	// row 3 (variable number 3) is basic with value 200 and upper bound 300
+	ind := []int32{0, 3}
+	if piv := lp.PrimRTest(ind, []float64{0, 1}, 1, 1e-9); piv != 1 {
+		t.Errorf("expected bounded ratio test (piv = 1) but got %d", piv)
+	}
+	if piv := lp.PrimRTest(ind, []float64{0, -1}, 1, 1e-9); piv != 0 {
+		t.Errorf("expected unbounded ratio test (piv = 0) but got %d", piv)
+	}
+	if piv := lp.DualRTest([]int32{0}, []float64{0}, 1, 1e-9); piv != 0 {
+		t.Errorf("expected 0 for empty row but got %d", piv)
+	}
+}
+
+func TestSmcpDeterministic(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	smcp.SetDeterministic(1) // the example needs 2 iterations
+	if err := lp.Simplex(smcp); err != EITLIM {
+		t.Errorf("expected %v but got %v", EITLIM, err)
+	}
+	smcp.SetDeterministic(100)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Errorf("Simplex error: %v", err)
+	}
+	CheckSolution(t, lp)
+}
+
+func TestReadWriteMPS(t *testing.T) {
+	lp := PrepareTestExample(t)
+	f1, err := ioutil.TempFile("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f1.Close()
+	defer os.Remove(f1.Name())
+	f2, err := ioutil.TempFile("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f2.Close()
+	defer os.Remove(f2.Name())
+	err = lp.WriteMPS(MPS_DECK, nil, f1.Name())
+	if err != nil {
+		t.Error(err)
+	}
+	err = lp.WriteMPS(MPS_FILE, NewMPSCP(), f2.Name())
+	if err != nil {
+		t.Error(err)
+	}
+	lp.Delete()
+
+	lp1 := New()
+	defer lp1.Delete()
+	err = lp1.ReadMPS(MPS_DECK, NewMPSCP(), f1.Name())
+	if err != nil {
+		t.Error(err)
+	} else {
+		lp1.SetObjDir(MAX)
+		CheckSimplexSolution(t, lp1)
+	}
+
+	lp2 := New()
+	defer lp2.Delete()
+	err = lp2.ReadMPS(MPS_FILE, nil, f2.Name())
+	if err != nil {
+		t.Error(err)
+	} else {
+		lp2.SetObjDir(MAX)
+		CheckSimplexSolution(t, lp2)
+	}
+}
+
+func TestReadWriteLP(t *testing.T) {
+	CheckReadWriteLP(t, nil)
+	CheckReadWriteLP(t, NewCPXCP())
+}
+
+func CheckReadWriteLP(t *testing.T, cpxcp *CPXCP) {
+	lp := PrepareTestExample(t)
+	f, err := ioutil.TempFile("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	err = lp.WriteLP(cpxcp, f.Name())
+	if err != nil {
+		t.Error(err)
+	}
+	lp.Delete()
+
+	lp1 := New()
+	defer lp1.Delete()
+	err = lp1.ReadLP(cpxcp, f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	CheckSimplexSolution(t, lp1)
+}
+
+func TestReadWriteProb(t *testing.T) {
+	lp := PrepareTestExample(t)
+	f, err := ioutil.TempFile("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	err = lp.WriteProb(0, f.Name())
+	if err != nil {
+		t.Error(err)
+	}
+	lp.Delete()
+
+	lp1 := New()
+	defer lp1.Delete()
+	err = lp1.ReadProb(0, f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	CheckSimplexSolution(t, lp1)
+}
 
 func TestSetGetColKind(t *testing.T) {
 	lp := New()
@@ -506,6 +1476,36 @@ func TestSetGetColKind(t *testing.T) {
 
 }
 
+func TestSmcpIocpClone(t *testing.T) {
+	smcp := NewSmcp()
+	smcp2 := smcp.Clone()
+	smcp2.SetPresolve(true)
+	if smcp.Presolve() || !smcp2.Presolve() {
+		t.Error("expected only the clone of Smcp to be modified")
+	}
+	iocp := NewIocp()
+	iocp2 := iocp.Clone()
+	iocp2.SetPresolve(true)
+	if iocp.Presolve() || !iocp2.Presolve() {
+		t.Error("expected only the clone of Iocp to be modified")
+	}
+}
+
+func TestClass(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if c := lp.Class(); c != ClassLP {
+		t.Errorf("expected ClassLP but got %d", c)
+	}
+	lp.SetColKind(2, BV)
+	if c := lp.Class(); c != ClassMIP {
+		t.Errorf("expected ClassMIP but got %d", c)
+	}
+	if n := lp.NumBin(); n != 1 {
+		t.Errorf("expected 1 binary column but got %d", n)
+	}
+}
+
 func TestIocp(t *testing.T) {
 	iocp := NewIocp()
 	for _, v := range []bool{false, true} {
@@ -599,13 +1599,204 @@ func TestIntop(t *testing.T) {
 	lp.Delete()
 }
 
-func CheckMipSolution(t *testing.T, lp *Prob) {
-	state := lp.MipStatus()
-	if state != OPT && state != FEAS {
-		t.Errorf("expected optimal solution, but got %d", lp.MipStatus())
-	}
+// PrepareMipTestExample prepares the problem of TestIntop for other
+// tests.
+func PrepareMipTestExample(t *testing.T) *Prob {
 
-	// z = 122.5; x1 = 40; x2 = 10.5; x3 = 19.5, x4 = 3
+	// Maximize
+	//
+	//      obj: x1 + 2 x2 + 3 x3 + x4
+	//
+	// Subject To
+	//
+	//      c1: 0 <= - x1 + x2 + x3 + 10 x4 <= 20
+	//      c2: 0 <= x1 - 3 x2 + x3 <= 30
+	//      c3: x2 - 3.5 x4 = 0
+	//
+	// Bounds
+	//
+	//      0 <= x1 <= 40
+	//      x2 >= 0
+	//      x3 >= 0
+	//      2 <= x4 <= 3
+	//
+	// Type
+	//
+	//      x1, x2, x3 real
+	//      x4 integer
+	//
+	// End
+
+	lp := New()
+	lp.SetProbName("sample")
+	lp.SetObjName("Z")
+	lp.SetObjDir(MAX)
+
+	if n := lp.AddRows(3); n != 1 {
+		t.Errorf("expected 0 but got %d", n)
+	}
+	lp.SetRowName(1, "c1")
+	lp.SetRowBnds(1, DB, 0.0, 20.0)
+	lp.SetRowName(2, "c2")
+	lp.SetRowBnds(2, DB, 0.0, 30.0)
+	lp.SetRowName(3, "c3")
+	lp.SetRowBnds(3, FX, 0.0, 0)
+
+	if n := lp.AddCols(4); n != 1 {
+		t.Errorf("expected 0 but got %d", n)
+	}
+
+	lp.SetColName(1, "x1")
+	lp.SetColBnds(1, DB, 0.0, 40.0)
+	lp.SetObjCoef(1, 1.0)
+	lp.SetColName(2, "x2")
+	lp.SetColBnds(2, LO, 0.0, 0.0)
+	lp.SetObjCoef(2, 2.0)
+	lp.SetColName(3, "x3")
+	lp.SetColBnds(3, LO, 0.0, 0.0)
+	lp.SetObjCoef(3, 3.0)
+	lp.SetColName(4, "x4")
+	lp.SetColBnds(4, DB, 2.0, 3.0)
+	lp.SetObjCoef(4, 1.0)
+	lp.SetColKind(4, IV)
+
+	ind := []int32{0, 1, 2, 3, 4}
+	mat := [][]float64{
+		{0, -1, 1.0, 1.0, 10},
+		{0, 1.0, -3.0, 1.0, 0.0},
+		{0, 0.0, 1.0, 0.0, -3.5}}
+	for i := 0; i < 3; i++ {
+		lp.SetMatRow(i+1, ind, mat[i])
+	}
+	return lp
+}
+
+func TestMipBestBound(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	if b := lp.MipBestBound(); !math.IsNaN(b) {
+		t.Errorf("expected NaN bound before solving but got %g", b)
+	}
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	iocp.SetMsgLev(MSG_ERR)
+	iocp.SetNodeLim(1)
+	err := lp.Intopt(iocp)
+	if err != nil && err != ESTOP {
+		t.Fatalf("Mip error: %v", err)
+	}
+	if st := lp.MipStatus(); st == OPT || st == FEAS {
+		// maximization so the bound is not below the incumbent
+		if b := lp.MipBestBound(); b < lp.MipObjVal()-1e-9 {
+			t.Errorf("bound %g is worse than incumbent %g", b, lp.MipObjVal())
+		}
+	}
+	iocp.SetNodeLim(0)
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	CheckClose(t, lp.MipBestBound(), 122.5)
+}
+
+func TestIocpDeterministic(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	iocp.SetMsgLev(MSG_ERR)
+	iocp.SetDeterministic(100)
+	if err := lp.Intopt(iocp); err != nil {
+		t.Errorf("Mip error: %v", err)
+	}
+	CheckMipSolution(t, lp)
+}
+
+func TestIntoptProgress(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	iocp.SetMsgLev(MSG_ERR)
+	progress, errc := lp.IntoptProgress(context.Background(), iocp)
+	for pr := range progress {
+		if !math.IsNaN(pr.Incumbent) && pr.Incumbent > 122.5+1e-10 {
+			t.Errorf("incumbent %g better than the optimum", pr.Incumbent)
+		}
+		if pr.Gap < 0 {
+			t.Errorf("got negative gap %g", pr.Gap)
+		}
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("Mip error: %v", err)
+	}
+	CheckMipSolution(t, lp)
+}
+
+func TestIntoptProgressCancel(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	iocp := NewIocp(WithMsgLev(MSG_ERR))
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	progress, errc := lp.IntoptProgress(ctx, iocp)
+	for range progress {
+	}
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+
+	iocp.SetCallback(func(tree *Tree) { panic("test") })
+	progress, errc = lp.IntoptProgress(context.Background(), iocp)
+	for range progress {
+	}
+	if err, ok := (<-errc).(*PanicError); !ok || err.Value != "test" {
+		t.Errorf("expected PanicError but got %v", err)
+	}
+}
+
+func TestMipSolutions(t *testing.T) {
+	for _, presolve := range []bool{false, true} {
+		lp := PrepareMipTestExample(t)
+		smcp := NewSmcp()
+		smcp.SetMsgLev(MSG_ERR)
+		if err := lp.Simplex(smcp); err != nil {
+			t.Fatalf("Simplex error: %v", err)
+		}
+		iocp := NewIocp()
+		iocp.SetPresolve(presolve)
+		iocp.SetMsgLev(MSG_ERR)
+		iocp.SetSolutionPool(3)
+		if err := lp.Intopt(iocp); err != nil {
+			t.Fatalf("Mip error: %v", err)
+		}
+		CheckMipSolution(t, lp)
+		sols := lp.MipSolutions()
+		if len(sols) == 0 || len(sols) > 3 {
+			t.Fatalf("expected 1 to 3 solutions but got %d", len(sols))
+		}
+		CheckClose(t, sols[0][0], lp.MipObjVal())
+		for j := 1; j <= lp.NumCols(); j++ {
+			CheckClose(t, sols[0][j], lp.MipColVal(j))
+		}
+		for k := 1; k < len(sols); k++ {
+			if sols[k][0] > sols[k-1][0] {
+				t.Errorf("solution %d is better than solution %d", k, k-1)
+			}
+		}
+		lp.Delete()
+	}
+}
+
+func CheckMipSolution(t *testing.T, lp *Prob) {
+	state := lp.MipStatus()
+	if state != OPT && state != FEAS {
+		t.Errorf("expected optimal solution, but got %d", lp.MipStatus())
+	}
+
+	// z = 122.5; x1 = 40; x2 = 10.5; x3 = 19.5, x4 = 3
 	CheckClose(t, lp.MipObjVal(), 122.5)
 	CheckClose(t, lp.MipColVal(1), 40)
 	CheckClose(t, lp.MipColVal(2), 10.5)
@@ -613,6 +1804,470 @@ func CheckMipSolution(t *testing.T, lp *Prob) {
 	CheckClose(t, lp.MipColVal(4), 3)
 }
 
+func TestColValue(t *testing.T) {
+	lp := PrepareTestExample(t)
+	CheckSimplexSolution(t, lp)
+	for j := 1; j <= lp.NumCols(); j++ {
+		CheckClose(t, lp.ColValue(j), lp.ColPrim(j))
+	}
+	lp.Delete()
+
+	lp = PrepareMipTestExample(t)
+	defer lp.Delete()
+	if n := lp.NumInt(); n != 1 {
+		t.Errorf("expected 1 integer column but got %d", n)
+	}
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	iocp.SetMsgLev(MSG_ERR)
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	CheckClose(t, lp.ColValue(4), 3)
+	for j := 1; j <= lp.NumCols(); j++ {
+		CheckClose(t, lp.ColValue(j), lp.MipColVal(j))
+	}
+}
+
+func TestRowSlack(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	CheckSimplexSolution(t, lp)
+	prim := []float64{0, 100, 600, 200}
+	for i := 1; i <= lp.NumRows(); i++ {
+		CheckClose(t, lp.RowPrim(i), prim[i])
+	}
+	CheckClose(t, lp.RowSlack(1), 0)
+	CheckClose(t, lp.RowSlack(2), 0)
+	CheckClose(t, lp.RowSlack(3), 100)
+	lp.SetRowBnds(3, DB, 150, 300)
+	CheckClose(t, lp.RowSlack(3), 50)
+	lp.SetRowBnds(3, LO, 120, 0)
+	CheckClose(t, lp.RowSlack(3), 80)
+	lp.SetRowBnds(3, FX, 250, 250)
+	CheckClose(t, lp.RowSlack(3), -50)
+	lp.SetRowBnds(3, FX, 150, 150)
+	CheckClose(t, lp.RowSlack(3), -50)
+	lp.SetRowBnds(3, FR, 0, 0)
+	if s := lp.RowSlack(3); !math.IsInf(s, 1) {
+		t.Errorf("expected +Inf slack for a free row but got %g", s)
+	}
+}
+
+func TestRootDuals(t *testing.T) {
+	lp := PrepareTestExample(t)
+	duals, err := lp.RootDuals()
+	if err != nil {
+		t.Fatalf("RootDuals error: %v", err)
+	}
+	expected := []float64{0, 10.0 / 3, 2.0 / 3, 0}
+	if len(duals) != len(expected) {
+		t.Fatalf("expected %d duals but got %d", len(expected), len(duals))
+	}
+	for i := 1; i < len(duals); i++ {
+		CheckClose(t, duals[i], expected[i])
+	}
+	if s := lp.Status(); s != UNDEF {
+		t.Errorf("expected the problem to be untouched but got status %v", s)
+	}
+	CheckSimplexSolution(t, lp)
+	for i := 1; i <= lp.NumRows(); i++ {
+		CheckClose(t, lp.RowDual(i), expected[i])
+	}
+	lp.Delete()
+
+	lp = PrepareMipTestExample(t)
+	defer lp.Delete()
+	q := lp.Relaxation()
+	if n := q.NumInt(); n != 0 {
+		t.Errorf("expected no integer columns in the relaxation but got %d", n)
+	}
+	q.Delete()
+	if duals, err = lp.RootDuals(); err != nil {
+		t.Fatalf("RootDuals error: %v", err)
+	}
+	if len(duals) != lp.NumRows()+1 {
+		t.Errorf("expected %d duals but got %d", lp.NumRows()+1, len(duals))
+	}
+	if n := lp.NumInt(); n != 1 {
+		t.Errorf("expected 1 integer column but got %d", n)
+	}
+}
+
+func TestIntegralityGaps(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+	CheckMipSolution(t, lp)
+	gaps := lp.IntegralityGaps()
+	if len(gaps) != lp.NumCols()+1 {
+		t.Fatalf("expected %d gaps but got %d", lp.NumCols()+1, len(gaps))
+	}
+	// the relaxation has x4 = 70/24
+	expected := []float64{0, 0, 0, 0, 3 - 70.0/24}
+	for j := 1; j < len(gaps); j++ {
+		CheckClose(t, gaps[j], expected[j])
+	}
+	CheckMipSolution(t, lp)
+}
+
+func TestWithRelaxed(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	err := lp.WithRelaxed([]int{4}, func() error {
+		if n := lp.NumInt(); n != 0 {
+			t.Errorf("expected no integer columns but got %d", n)
+		}
+		return lp.Simplex(nil)
+	})
+	if err != nil {
+		t.Fatalf("WithRelaxed error: %v", err)
+	}
+	CheckClose(t, lp.ColPrim(4), 70.0/24)
+	if kind := lp.ColKind(4); kind != IV {
+		t.Errorf("expected kind IV but got %d", kind)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		lp.WithRelaxed([]int{4}, func() error { panic("test") })
+	}()
+	if kind := lp.ColKind(4); kind != IV {
+		t.Errorf("expected kind IV after panic but got %d", kind)
+	}
+}
+
+func TestLastSolveTime(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if d := lp.LastSolveTime(); d != 0 {
+		t.Errorf("expected 0 before solving but got %v", d)
+	}
+	CheckSimplexSolution(t, lp)
+	if d := lp.LastSolveTime(); d <= 0 {
+		t.Errorf("expected positive solve time but got %v", d)
+	}
+}
+
+func TestMIPFeasibilityHint(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	if !lp.HasIntegerVars() {
+		t.Error("expected integer columns")
+	}
+	// x4 = 70/24 in the relaxation
+	ok, err := lp.MIPFeasibilityHint()
+	if err != nil {
+		t.Fatalf("MIPFeasibilityHint error: %v", err)
+	}
+	if ok {
+		t.Error("expected fractional relaxation")
+	}
+	lp.SetColBnds(4, FX, 2, 2)
+	if ok, err = lp.MIPFeasibilityHint(); err != nil {
+		t.Fatalf("MIPFeasibilityHint error: %v", err)
+	}
+	if !ok {
+		t.Error("expected integral relaxation")
+	}
+	if s := lp.Status(); s != UNDEF {
+		t.Errorf("expected the problem to be untouched but got status %v", s)
+	}
+	lp.SetColBnds(1, LO, 100, 0)
+	if _, err = lp.MIPFeasibilityHint(); err != ErrNotOptimal {
+		t.Errorf("expected ErrNotOptimal but got %v", err)
+	}
+	lp1 := PrepareTestExample(t)
+	defer lp1.Delete()
+	if lp1.HasIntegerVars() {
+		t.Error("expected no integer columns")
+	}
+}
+
+func TestLastSolveOutcome(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if o := lp.LastSolveOutcome(); o != NotSolved {
+		t.Errorf("expected NotSolved but got %d", o)
+	}
+	CheckSimplexSolution(t, lp)
+	if o := lp.LastSolveOutcome(); o != Optimal {
+		t.Errorf("expected Optimal but got %d", o)
+	}
+	lp1 := PrepareTestExample(t)
+	defer lp1.Delete()
+	smcp := NewSmcp(WithMsgLev(MSG_ERR))
+	smcp.SetItLim(1)
+	if err := lp1.Simplex(smcp); err != EITLIM {
+		t.Fatalf("expected EITLIM but got %v", err)
+	}
+	if o := lp1.LastSolveOutcome(); o != Feasible {
+		t.Errorf("expected Feasible but got %d", o)
+	}
+	lp.SetRowBnds(1, LO, 1000, 0)
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if o := lp.LastSolveOutcome(); o != Infeasible {
+		t.Errorf("expected Infeasible but got %d", o)
+	}
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR), WithPresolve(true))); err != ENOPFS {
+		t.Fatalf("expected ENOPFS but got %v", err)
+	}
+	if o := lp.LastSolveOutcome(); o != Infeasible {
+		t.Errorf("expected Infeasible but got %d", o)
+	}
+
+	mip := PrepareMipTestExample(t)
+	defer mip.Delete()
+	iocp := NewIocp(WithMsgLev(MSG_ERR), WithPresolve(true))
+	if err := mip.Intopt(iocp); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+	if o := mip.LastSolveOutcome(); o != Optimal {
+		t.Errorf("expected Optimal but got %d", o)
+	}
+}
+
+func TestRowColNnz(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	for i, expected := range []int{0, 4, 3, 2} {
+		if i > 0 && lp.RowNnz(i) != expected {
+			t.Errorf("expected %d nonzeros in row %d but got %d", expected, i, lp.RowNnz(i))
+		}
+	}
+	for j, expected := range []int{0, 2, 3, 2, 2} {
+		if j > 0 && lp.ColNnz(j) != expected {
+			t.Errorf("expected %d nonzeros in column %d but got %d", expected, j, lp.ColNnz(j))
+		}
+	}
+}
+
+func TestMatZeroBased(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(2)
+	lp.AddCols(3)
+	lp.SetMatRow0(1, []int32{1, 3}, []float64{2, 4})
+	ind, val := lp.MatRow0(1)
+	if !CmpIndicesData(append([]int32{0}, ind...), append([]float64{0}, val...), []int32{0, 1, 3}, []float64{0, 2, 4}) {
+		t.Errorf("unexpected row elements (%v, %v)", ind, val)
+	}
+	lp.SetMatCol0(2, []int32{2}, []float64{5})
+	ind, val = lp.MatCol0(2)
+	if len(ind) != 1 || ind[0] != 2 || val[0] != 5 {
+		t.Errorf("expected ([2], [5]) but got (%v, %v)", ind, val)
+	}
+	lp.LoadMatrix0([]int32{1, 2}, []int32{2, 3}, []float64{1, 7})
+	if n := lp.NumNz(); n != 2 {
+		t.Errorf("expected 2 nonzero elements but got %d", n)
+	}
+	ind, val = lp.MatCol0(3)
+	if len(ind) != 1 || ind[0] != 2 || val[0] != 7 {
+		t.Errorf("expected ([2], [7]) but got (%v, %v)", ind, val)
+	}
+	if ind, _ := lp.MatRow0(1); len(ind) != 1 {
+		t.Errorf("expected 1 element in row 1 but got %d", len(ind))
+	}
+}
+
+func TestMinimizing(t *testing.T) {
+	lp := New()
+	defer lp.Delete()
+	if !lp.Minimizing() {
+		t.Error("expected minimization by default")
+	}
+	lp.SetMinimizing(false)
+	if d := lp.ObjDir(); d != MAX || lp.Minimizing() {
+		t.Errorf("expected MAX but got %v", d)
+	}
+	lp.SetMinimizing(true)
+	if d := lp.ObjDir(); d != MIN || !lp.Minimizing() {
+		t.Errorf("expected MIN but got %v", d)
+	}
+}
+
+func TestNegate(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	lp.SetObjCoef(0, 5)
+	lp.Negate()
+	if d := lp.ObjDir(); d != MIN {
+		t.Errorf("expected MIN but got %v", d)
+	}
+	CheckClose(t, lp.ObjCoef(0), -5)
+	CheckClose(t, lp.ObjCoef(1), -10)
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	CheckClose(t, lp.ObjVal(), -(733 + 1.0/3 + 5))
+	CheckClose(t, lp.ColPrim(1), 100.0/3)
+	CheckClose(t, lp.ColPrim(2), 200.0/3)
+	lp.Negate()
+	if d := lp.ObjDir(); d != MAX {
+		t.Errorf("expected MAX but got %v", d)
+	}
+	CheckClose(t, lp.ObjCoef(0), 5)
+	CheckClose(t, lp.ObjCoef(1), 10)
+}
+
+func TestRankByReducedCost(t *testing.T) {
+	inf := math.Inf(1)
+	lp := NewDense([]float64{3, 1, 2}, [][]float64{{1, 1, 1}},
+		[]float64{0, 0, 0}, []float64{inf, inf, inf}, []float64{1}, []float64{inf}, MIN)
+	defer lp.Delete()
+	for _, dir := range []ObjDir{MIN, MAX} {
+		if dir == MAX {
+			lp.Negate()
+		}
+		if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+			t.Fatalf("Simplex error: %v", err)
+		}
+		// reduced costs are 2, 0, 1 (or -2, 0, -1 when maximizing)
+		rank := lp.RankByReducedCost()
+		if !reflect.DeepEqual(rank, []int{2, 3, 1}) {
+			t.Errorf("expected [2 3 1] but got %v (direction %v)", rank, dir)
+		}
+	}
+}
+
+func TestObjBound(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if b := lp.ObjBound(); !math.IsInf(b, 1) {
+		t.Errorf("expected +Inf bound before solving but got %g", b)
+	}
+	lp.SetObjCoef(0, 5)
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if math.Abs(lp.ObjBound()-lp.ObjVal()) > 1e-9 {
+		t.Errorf("expected bound %g but got %g", lp.ObjVal(), lp.ObjBound())
+	}
+
+	// with bounded columns the bound is finite for any duals
+	for j := 1; j <= lp.NumCols(); j++ {
+		lp.SetColBnds(j, DB, 0, 100)
+	}
+	lp.SetObjDir(MIN)
+	lp.SetObjCoefs([]int{1, 2, 3}, []float64{-10, -6, -4})
+	err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR), WithItLim(1)))
+	if err != nil && err != EITLIM {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if b := lp.ObjBound(); math.IsInf(b, 0) || b > 5-(733+1.0/3)+1e-9 {
+		t.Errorf("expected a finite lower bound below the optimum but got %g", b)
+	}
+}
+
+func TestDualsByName(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	lp.AddRows(1) // row without a name
+	CheckSimplexSolution(t, lp)
+	rows := lp.RowDualsByName()
+	if len(rows) != 3 {
+		t.Errorf("expected 3 rows but got %v", rows)
+	}
+	CheckClose(t, rows["p"], 10.0/3)
+	CheckClose(t, rows["q"], 2.0/3)
+	CheckClose(t, rows["r"], 0)
+	cols := lp.ColDualsByName()
+	if len(cols) != 3 {
+		t.Errorf("expected 3 columns but got %v", cols)
+	}
+	for j := 1; j <= lp.NumCols(); j++ {
+		CheckClose(t, cols[lp.ColName(j)], lp.ColDual(j))
+	}
+	CheckClose(t, cols["x2"], -8.0/3)
+}
+
+func TestNormalizeObjective(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	lp.SetObjCoefs([]int{1, 2, 3}, []float64{1e10, 6e9, 4e9})
+	scale := lp.NormalizeObjective()
+	CheckClose(t, scale, 1e10)
+	CheckClose(t, lp.ObjCoef(1), 1)
+	CheckClose(t, lp.ObjCoef(2), 0.6)
+	CheckClose(t, lp.ObjCoef(3), 0.4)
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	CheckClose(t, lp.ColPrim(1), 100.0/3)
+	CheckClose(t, lp.ObjVal()*scale/1e9, 733+1.0/3)
+
+	lp.SetObjCoefs([]int{1, 2, 3}, []float64{0, 0, 0})
+	if scale := lp.NormalizeObjective(); scale != 1 {
+		t.Errorf("expected scale 1 for zero objective but got %g", scale)
+	}
+}
+
+func TestNonzeroCols(t *testing.T) {
+	lp := PrepareTestExample(t)
+	CheckSimplexSolution(t, lp)
+	if cols := lp.NonzeroCols(1e-9); !reflect.DeepEqual(cols, []int{1, 2}) {
+		t.Errorf("expected [1 2] but got %v", cols)
+	}
+	if cols := lp.NonzeroCols(50); !reflect.DeepEqual(cols, []int{2}) {
+		t.Errorf("expected [2] but got %v", cols)
+	}
+	lp.Delete()
+
+	lp = PrepareMipTestExample(t)
+	defer lp.Delete()
+	if err := lp.Intopt(NewIocp(WithPresolve(true), WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	if cols := lp.MipNonzeroCols(1e-9); !reflect.DeepEqual(cols, []int{1, 2, 3, 4}) {
+		t.Errorf("expected [1 2 3 4] but got %v", cols)
+	}
+	if cols := lp.MipNonzeroCols(20); !reflect.DeepEqual(cols, []int{1}) {
+		t.Errorf("expected [1] but got %v", cols)
+	}
+}
+
+func TestIntFeas1(t *testing.T) {
+	// x1 + x2 + x3 = 2, x1 + x2 <= 1, maximize x1 + 2 x2 + 3 x3
+	lp := New()
+	defer lp.Delete()
+	lp.SetObjDir(MAX)
+	lp.AddRows(2)
+	lp.SetRowBnds(1, FX, 2, 2)
+	lp.SetRowBnds(2, UP, 0, 1)
+	lp.AddCols(3)
+	for j := 1; j <= 3; j++ {
+		lp.SetColKind(j, BV)
+		lp.SetObjCoef(j, float64(j))
+	}
+	lp.SetMatRow(1, []int32{0, 1, 2, 3}, []float64{0, 1, 1, 1})
+	lp.SetMatRow(2, []int32{0, 1, 2}, []float64{0, 1, 1})
+	if err := lp.IntFeas1(false, 0); err != nil {
+		t.Fatalf("IntFeas1 error: %v", err)
+	}
+	if lp.MipStatus() != FEAS && lp.MipStatus() != OPT {
+		t.Fatalf("expected feasible solution, but got %d", lp.MipStatus())
+	}
+	CheckClose(t, lp.MipColVal(3), 1)
+	CheckClose(t, lp.MipColVal(1)+lp.MipColVal(2), 1)
+	// no solution is better than the optimal one (x2 = x3 = 1)
+	if err := lp.IntFeas1(true, 5); err != nil {
+		t.Fatalf("IntFeas1 error: %v", err)
+	}
+	if lp.MipStatus() != NOFEAS {
+		t.Errorf("expected no feasible solution, but got %d", lp.MipStatus())
+	}
+}
+
 func TestGarbageCollection(t *testing.T) {
 	// this loop should create enough objects to trigger garbage collection
 	for i := 0; i < 2000; i++ {
@@ -622,3 +2277,52 @@ func TestGarbageCollection(t *testing.T) {
 		lp2.Delete()
 	}
 }
+
+func TestFinalizerDeleteStress(t *testing.T) {
+	// GLPK is not thread safe so the problems are used from a single
+	// goroutine while the garbage collector finalizes the others
+	for i := 0; i < 4000; i++ {
+		lp := New()
+		lp.AddRows(1)
+		q := lp.Copy(false)
+		if i%2 == 0 {
+			lp.Delete()
+		}
+		q.Delete()
+		q.Delete() // deleting twice is allowed
+		if i%50 == 0 {
+			runtime.GC()
+		}
+	}
+	runtime.GC()
+	New().Delete() // deletes the problems queued by the finalizers
+	for i := 0; i < 4000; i++ {
+		g := NewGraph()
+		g.AddVertices(2)
+		g.AddArc(1, 2, 0, 1, 1)
+		if i%2 == 0 {
+			g.Delete()
+		}
+		if i%50 == 0 {
+			runtime.GC()
+		}
+	}
+	runtime.GC()
+	NewGraph().Delete() // deletes the graphs queued by the finalizers
+}
+
+func TestFreeEnv(t *testing.T) {
+	lp := PrepareTestExample(t)
+	g := NewGraph()
+	func() {
+		New().AddRows(1) // left to the garbage collector
+	}()
+	runtime.GC()
+	FreeEnv()
+	lp.Delete() // already freed by FreeEnv
+	g.Delete()
+	runtime.GC()
+	lp = PrepareTestExample(t)
+	defer lp.Delete()
+	CheckSimplexSolution(t, lp)
+}
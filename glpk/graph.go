@@ -0,0 +1,470 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// #include <glpk.h>
+// #include <stddef.h>
+// #include <stdlib.h>
+//
+// typedef struct { double rhs; int num; } v_data;
+// typedef struct { double low, cap, cost; } a_data;
+//
+// static glp_graph *create_graph(void) {
+//	return glp_create_graph(sizeof(v_data), sizeof(a_data));
+// }
+//
+// static void erase_graph(glp_graph *G) {
+//	glp_erase_graph(G, sizeof(v_data), sizeof(a_data));
+// }
+//
+// static int num_vertices(glp_graph *G) { return G->nv; }
+// static int num_arcs(glp_graph *G) { return G->na; }
+//
+// static double vertex_rhs(glp_graph *G, int i) {
+//	return ((v_data *)G->v[i]->data)->rhs;
+// }
+//
+// static void set_vertex_rhs(glp_graph *G, int i, double rhs) {
+//	((v_data *)G->v[i]->data)->rhs = rhs;
+// }
+//
+// static void add_arc(glp_graph *G, int i, int j, double low, double cap, double cost) {
+//	a_data *d = (a_data *)glp_add_arc(G, i, j)->data;
+//	d->low = low;
+//	d->cap = cap;
+//	d->cost = cost;
+// }
+//
+// static void get_arcs(glp_graph *G, int tail[], int head[], double low[], double cap[], double cost[]) {
+//	int i, k = 0;
+//	glp_arc *a;
+//	for (i = 1; i <= G->nv; i++) {
+//		for (a = G->v[i]->out; a != NULL; a = a->t_next) {
+//			a_data *d = (a_data *)a->data;
+//			k++;
+//			tail[k] = a->tail->i;
+//			head[k] = a->head->i;
+//			low[k] = d->low;
+//			cap[k] = d->cap;
+//			cost[k] = d->cost;
+//		}
+//	}
+// }
+//
+// static void get_vertex_num(glp_graph *G, int num[]) {
+//	int i;
+//	for (i = 1; i <= G->nv; i++) {
+//		num[i] = ((v_data *)G->v[i]->data)->num;
+//	}
+// }
+//
+// static void weak_comp(glp_graph *G, int num[]) {
+//	glp_weak_comp(G, offsetof(v_data, num));
+//	get_vertex_num(G, num);
+// }
+//
+// static void strong_comp(glp_graph *G, int num[]) {
+//	glp_strong_comp(G, offsetof(v_data, num));
+//	get_vertex_num(G, num);
+// }
+//
+// static void top_sort(glp_graph *G, int num[]) {
+//	glp_top_sort(G, offsetof(v_data, num));
+//	get_vertex_num(G, num);
+// }
+//
+// static int netgen(glp_graph *G, const int parm[]) {
+//	return glp_netgen(G, offsetof(v_data, rhs), offsetof(a_data, cap),
+//		offsetof(a_data, cost), parm);
+// }
+//
+// static int gridgen(glp_graph *G, const int parm[]) {
+//	return glp_gridgen(G, offsetof(v_data, rhs), offsetof(a_data, cap),
+//		offsetof(a_data, cost), parm);
+// }
+//
+// static int read_mincost(glp_graph *G, const char *fname) {
+//	return glp_read_mincost(G, offsetof(v_data, rhs), offsetof(a_data, low),
+//		offsetof(a_data, cap), offsetof(a_data, cost), fname);
+// }
+//
+// static int read_maxflow(glp_graph *G, int *s, int *t, const char *fname) {
+//	return glp_read_maxflow(G, s, t, offsetof(a_data, cap), fname);
+// }
+import "C"
+
+type graph struct {
+	g   *C.glp_graph
+	gen int // environment generation (see envGen)
+}
+
+// Graph represents a directed graph (network). Use glpk.NewGraph() to
+// create a new graph.
+//
+// Each vertex of the graph holds its supply (the right-hand side of
+// the flow conservation constraint, see VertexRhs) and each arc holds
+// its lower bound, capacity (upper bound), and per-unit cost of the
+// flow (see Arcs). These are the data used by the network routines of
+// GLPK.
+//
+// As with Prob a graph which was not deleted with Graph.Delete() is
+// deleted after garbage collection (by the next call to New, NewGraph,
+// or Delete, see Prob.Delete()).
+type Graph struct {
+	g    *graph
+	s, t int // source and sink read by ReadMaxflowDIMACS
+}
+
+// Arc describes an arc of a graph.
+type Arc struct {
+	Tail, Head int     // (1-based) numbers of the tail and head vertices
+	Low        float64 // lower bound of the arc flow
+	Cap        float64 // capacity (upper bound) of the arc flow
+	Cost       float64 // per-unit cost of the arc flow
+}
+
+// NewGraph creates a new empty graph.
+func NewGraph() *Graph {
+	deleteFinalized()
+	g := &graph{C.create_graph(), currentEnvGen()}
+	runtime.SetFinalizer(g, finalizeGraph)
+	return &Graph{g: g}
+}
+
+// finalizeGraph is called on garbage collection of a graph which was
+// not deleted with Graph.Delete(). As finalizeProb it only queues the
+// graph to be deleted by deleteFinalized.
+func finalizeGraph(g *graph) {
+	if g.g != nil {
+		envMu.Lock()
+		if g.gen == envGen {
+			finalizedGraphs = append(finalizedGraphs, g.g)
+		}
+		envMu.Unlock()
+		g.g = nil
+	}
+}
+
+// Delete deletes a graph. Calling Delete on a deleted graph will have
+// no effect (It is save to do so). But calling any other method on a
+// deleted graph will panic.
+func (g *Graph) Delete() {
+	deleteFinalized()
+	if g.g.g != nil {
+		if g.g.gen == currentEnvGen() {
+			C.glp_delete_graph(g.g.g)
+		}
+		g.g.g = nil
+		runtime.SetFinalizer(g.g, nil)
+	}
+}
+
+// Erase erases the graph. After erasing the graph is empty as if it
+// were created with glpk.NewGraph().
+func (g *Graph) Erase() {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	C.erase_graph(g.g.g)
+	g.s, g.t = 0, 0
+}
+
+// NumVertices returns number of vertices.
+func (g *Graph) NumVertices() int {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	return int(C.num_vertices(g.g.g))
+}
+
+// NumArcs returns number of arcs.
+func (g *Graph) NumArcs() int {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	return int(C.num_arcs(g.g.g))
+}
+
+// AddVertices adds vertices. Returns (1-based) number of the first of
+// the added vertices.
+func (g *Graph) AddVertices(nadd int) int {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	return int(C.glp_add_vertices(g.g.g, C.int(nadd)))
+}
+
+// AddArc adds an arc from i-th to j-th vertex with the given lower
+// bound, capacity, and per-unit cost of the flow.
+func (g *Graph) AddArc(i, j int, low, capacity, cost float64) {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	C.add_arc(g.g.g, C.int(i), C.int(j), C.double(low), C.double(capacity), C.double(cost))
+}
+
+// VertexRhs returns the supply of i-th vertex (negative value means
+// demand).
+func (g *Graph) VertexRhs(i int) float64 {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	return float64(C.vertex_rhs(g.g.g, C.int(i)))
+}
+
+// SetVertexRhs sets the supply of i-th vertex (negative value means
+// demand).
+func (g *Graph) SetVertexRhs(i int, rhs float64) {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	C.set_vertex_rhs(g.g.g, C.int(i), C.double(rhs))
+}
+
+// Arcs returns all arcs of the graph ordered by their tail vertices.
+func (g *Graph) Arcs() []Arc {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	na := int(C.num_arcs(g.g.g))
+	tail := make([]int32, na+1)
+	head := make([]int32, na+1)
+	low := make([]float64, na+1)
+	capa := make([]float64, na+1)
+	cost := make([]float64, na+1)
+	tailH := (*reflect.SliceHeader)(unsafe.Pointer(&tail))
+	headH := (*reflect.SliceHeader)(unsafe.Pointer(&head))
+	lowH := (*reflect.SliceHeader)(unsafe.Pointer(&low))
+	capH := (*reflect.SliceHeader)(unsafe.Pointer(&capa))
+	costH := (*reflect.SliceHeader)(unsafe.Pointer(&cost))
+	C.get_arcs(g.g.g, (*C.int)(unsafe.Pointer(tailH.Data)), (*C.int)(unsafe.Pointer(headH.Data)), (*C.double)(unsafe.Pointer(lowH.Data)), (*C.double)(unsafe.Pointer(capH.Data)), (*C.double)(unsafe.Pointer(costH.Data)))
+	arcs := make([]Arc, na)
+	for k := range arcs {
+		arcs[k] = Arc{int(tail[k+1]), int(head[k+1]), low[k+1], capa[k+1], cost[k+1]}
+	}
+	return arcs
+}
+
+// WeakComponents finds all weakly connected components of the graph.
+// It returns a slice comp where comp[i] is the (1-based) number of the
+// component containing i-th vertex, for i=1..NumVertices() (comp[0] is
+// not used). The number of components is the largest value in comp.
+func (g *Graph) WeakComponents() []int {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	return g.components(false)
+}
+
+// StrongComponents finds all strongly connected components of the
+// graph. It returns a slice comp where comp[i] is the (1-based) number
+// of the component containing i-th vertex, for i=1..NumVertices()
+// (comp[0] is not used). The number of components is the largest
+// value in comp.
+func (g *Graph) StrongComponents() []int {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	return g.components(true)
+}
+
+// TopSort finds a topological ordering of the graph vertices. It
+// returns a slice num where num[i] is the (1-based) position of i-th
+// vertex in the ordering, for i=1..NumVertices() (num[0] is not
+// used), i.e. for every arc (i, j) num[i] < num[j]. If the graph is
+// not acyclic the vertices which cannot be sorted (those in cycles
+// and those reachable from them) have num[i] = 0.
+func (g *Graph) TopSort() []int {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	return g.vertexNums(func(G *C.glp_graph, num *C.int) { C.top_sort(G, num) })
+}
+
+func (g *Graph) components(strong bool) []int {
+	if strong {
+		return g.vertexNums(func(G *C.glp_graph, num *C.int) { C.strong_comp(G, num) })
+	}
+	return g.vertexNums(func(G *C.glp_graph, num *C.int) { C.weak_comp(G, num) })
+}
+
+// vertexNums calls f which stores an integer for each vertex and
+// returns these integers.
+func (g *Graph) vertexNums(f func(*C.glp_graph, *C.int)) []int {
+	num := make([]int32, C.num_vertices(g.g.g)+1)
+	numH := (*reflect.SliceHeader)(unsafe.Pointer(&num))
+	f(g.g.g, (*C.int)(unsafe.Pointer(numH.Data)))
+	res := make([]int, len(num))
+	for i := 1; i < len(num); i++ {
+		res[i] = int(num[i])
+	}
+	return res
+}
+
+// Terminals returns (1-based) numbers of the source and sink vertices
+// as read by ReadMaxflowDIMACS (or zeros if they were not read).
+func (g *Graph) Terminals() (s, t int) {
+	return g.s, g.t
+}
+
+// ReadMincostDIMACS reads the minimum cost flow problem data from a
+// file in DIMACS format. The vertex supplies and the arc lower bounds,
+// capacities, and costs are stored in the graph (replacing its
+// previous contents).
+func (g *Graph) ReadMincostDIMACS(filename string) error {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	fname := C.CString(filename)
+	defer C.free(unsafe.Pointer(fname))
+	g.s, g.t = 0, 0
+	return fileOp("read", filename, "DIMACS min-cost flow reading error", func() C.int {
+		return C.read_mincost(g.g.g, fname)
+	})
+}
+
+// ReadMaxflowDIMACS reads the maximum flow problem data from a file in
+// DIMACS format. The arc capacities are stored in the graph
+// (replacing its previous contents) and the source and sink can be
+// obtained with Terminals.
+func (g *Graph) ReadMaxflowDIMACS(filename string) error {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	fname := C.CString(filename)
+	defer C.free(unsafe.Pointer(fname))
+	var s, t C.int
+	g.s, g.t = 0, 0
+	if err := fileOp("read", filename, "DIMACS maximum flow reading error", func() C.int {
+		return C.read_maxflow(g.g.g, &s, &t, fname)
+	}); err != nil {
+		return err
+	}
+	g.s, g.t = int(s), int(t)
+	return nil
+}
+
+// NetgenParams represents parameters of the Klingman's network problem
+// generator (NETGEN), see Graph.Netgen.
+type NetgenParams struct {
+	Seed          int // 8-digit positive random number seed
+	Problem       int // 8-digit problem id number
+	Nodes         int // total number of nodes
+	Sources       int // total number of source nodes (including transshipment nodes)
+	Sinks         int // total number of sink nodes (including transshipment nodes)
+	Arcs          int // number of arcs
+	MinCost       int // minimum cost for arcs
+	MaxCost       int // maximum cost for arcs
+	Supply        int // total supply
+	TranspSources int // number of transshipment source nodes
+	TranspSinks   int // number of transshipment sink nodes
+	PctMaxCost    int // percentage of skeleton arcs to be given the maximum cost
+	PctCap        int // percentage of arcs to be capacitated
+	MinCap        int // minimum upper bound for capacitated arcs
+	MaxCap        int // maximum upper bound for capacitated arcs
+}
+
+// Netgen generates a network problem instance (replacing the previous
+// contents of the graph) with the Klingman's network problem generator
+// (NETGEN) as described by the params argument. The vertex supplies and
+// the arc capacities and costs are stored in the graph. The same
+// params always produce the same instance which makes NETGEN useful
+// for reproducible benchmarks. Depending on the params the generated
+// instance is a minimum cost flow, transportation, or assignment
+// problem.
+//
+// Returns glpk.EDATA if the params are inconsistent.
+func (g *Graph) Netgen(params NetgenParams) error {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	parm := []int32{0, int32(params.Seed), int32(params.Problem),
+		int32(params.Nodes), int32(params.Sources), int32(params.Sinks),
+		int32(params.Arcs), int32(params.MinCost), int32(params.MaxCost),
+		int32(params.Supply), int32(params.TranspSources),
+		int32(params.TranspSinks), int32(params.PctMaxCost),
+		int32(params.PctCap), int32(params.MinCap), int32(params.MaxCap)}
+	parmH := (*reflect.SliceHeader)(unsafe.Pointer(&parm))
+	g.s, g.t = 0, 0
+	if C.netgen(g.g.g, (*C.int)(unsafe.Pointer(parmH.Data))) != 0 {
+		return EDATA
+	}
+	return nil
+}
+
+// GridgenParams represents parameters of the grid-like network problem
+// generator (GRIDGEN), see Graph.Gridgen.
+type GridgenParams struct {
+	TwoWays   bool // whether links in both directions should be generated
+	Seed      int  // random number seed (a positive integer)
+	Nodes     int  // number of nodes (may be slightly changed to make the network a grid)
+	Width     int  // grid width
+	Sources   int  // number of sources
+	Sinks     int  // number of sinks
+	AvgDegree int  // average degree
+	Flow      int  // total flow
+	CostDist  int  // distribution of arc costs: 1 - uniform, 2 - exponential
+	MinCost   int  // lower bound for arc cost (uniform), 100*lambda (exponential)
+	MaxCost   int  // upper bound for arc cost (uniform), not used (exponential)
+	CapDist   int  // distribution of arc capacities: 1 - uniform, 2 - exponential
+	MinCap    int  // lower bound for arc capacity (uniform), 100*lambda (exponential)
+	MaxCap    int  // upper bound for arc capacity (uniform), not used (exponential)
+}
+
+// Gridgen generates a grid-like network problem instance (replacing
+// the previous contents of the graph) with the GRIDGEN generator as
+// described by the params argument. The vertex supplies and the arc
+// capacities and costs are stored in the graph. The same params always
+// produce the same instance.
+//
+// Returns glpk.EDATA if the params are inconsistent.
+func (g *Graph) Gridgen(params GridgenParams) error {
+	if g.g.g == nil {
+		panic("Graph method called on a deleted graph")
+	}
+	var twoWays int32
+	if params.TwoWays {
+		twoWays = 1
+	}
+	parm := []int32{0, twoWays, int32(params.Seed), int32(params.Nodes),
+		int32(params.Width), int32(params.Sources), int32(params.Sinks),
+		int32(params.AvgDegree), int32(params.Flow),
+		int32(params.CostDist), int32(params.MinCost),
+		int32(params.MaxCost), int32(params.CapDist),
+		int32(params.MinCap), int32(params.MaxCap)}
+	parmH := (*reflect.SliceHeader)(unsafe.Pointer(&parm))
+	g.s, g.t = 0, 0
+	if C.gridgen(g.g.g, (*C.int)(unsafe.Pointer(parmH.Data))) != 0 {
+		return EDATA
+	}
+	return nil
+}
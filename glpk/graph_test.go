@@ -0,0 +1,244 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func WriteTempFile(t *testing.T, data string) string {
+	f, err := ioutil.TempFile("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		os.Remove(f.Name())
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func TestGraphAddArc(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	if n := g.AddVertices(3); n != 1 {
+		t.Errorf("expected 1 but got %d", n)
+	}
+	g.SetVertexRhs(1, 5)
+	g.SetVertexRhs(3, -5)
+	g.AddArc(1, 2, 0, 4, 2)
+	g.AddArc(2, 3, 1, 5, 3)
+	if n := g.NumVertices(); n != 3 {
+		t.Errorf("Got %d vertices expected 3", n)
+	}
+	if n := g.NumArcs(); n != 2 {
+		t.Errorf("Got %d arcs expected 2", n)
+	}
+	if rhs := g.VertexRhs(3); rhs != -5 {
+		t.Errorf("Got rhs %g but -5 was set", rhs)
+	}
+	arcs := g.Arcs()
+	expected := []Arc{{1, 2, 0, 4, 2}, {2, 3, 1, 5, 3}}
+	if len(arcs) != len(expected) {
+		t.Fatalf("Got arcs %v expected %v", arcs, expected)
+	}
+	for i := range arcs {
+		if arcs[i] != expected[i] {
+			t.Errorf("Got arc %v expected %v", arcs[i], expected[i])
+		}
+	}
+	g.Erase()
+	if n := g.NumVertices(); n != 0 {
+		t.Errorf("Got %d vertices expected 0", n)
+	}
+}
+
+const mincostDIMACS = `c sample min-cost flow problem
+p min 4 5
+n 1 4
+n 4 -4
+a 1 2 0 4 2
+a 1 3 0 2 2
+a 2 3 0 2 1
+a 2 4 0 3 3
+a 3 4 0 5 1
+`
+
+const maxflowDIMACS = `c sample maximum flow problem
+p max 4 5
+n 1 s
+n 4 t
+a 1 2 4
+a 1 3 2
+a 2 3 2
+a 2 4 3
+a 3 4 5
+`
+
+func TestReadMincostDIMACS(t *testing.T) {
+	name := WriteTempFile(t, mincostDIMACS)
+	defer os.Remove(name)
+	g := NewGraph()
+	defer g.Delete()
+	if err := g.ReadMincostDIMACS(name); err != nil {
+		t.Fatal(err)
+	}
+	if n := g.NumVertices(); n != 4 {
+		t.Errorf("Got %d vertices expected 4", n)
+	}
+	if n := g.NumArcs(); n != 5 {
+		t.Errorf("Got %d arcs expected 5", n)
+	}
+	if rhs := g.VertexRhs(1); rhs != 4 {
+		t.Errorf("Got rhs %g expected 4", rhs)
+	}
+	if rhs := g.VertexRhs(4); rhs != -4 {
+		t.Errorf("Got rhs %g expected -4", rhs)
+	}
+	for _, a := range g.Arcs() {
+		if a.Tail == 2 && a.Head == 4 && (a.Cap != 3 || a.Cost != 3) {
+			t.Errorf("Got arc %v expected capacity 3 and cost 3", a)
+		}
+	}
+	if err := g.ReadMincostDIMACS(name + ".nonexistent"); err == nil {
+		t.Error("expected error reading nonexistent file")
+	}
+}
+
+func TestReadMaxflowDIMACS(t *testing.T) {
+	name := WriteTempFile(t, maxflowDIMACS)
+	defer os.Remove(name)
+	g := NewGraph()
+	defer g.Delete()
+	if err := g.ReadMaxflowDIMACS(name); err != nil {
+		t.Fatal(err)
+	}
+	if n := g.NumArcs(); n != 5 {
+		t.Errorf("Got %d arcs expected 5", n)
+	}
+	if s, sink := g.Terminals(); s != 1 || sink != 4 {
+		t.Errorf("Got terminals (%d, %d) expected (1, 4)", s, sink)
+	}
+}
+
+func CheckComponents(t *testing.T, comp []int, expected []int) {
+	if len(comp) != len(expected) {
+		t.Fatalf("Got components %v expected %v", comp, expected)
+	}
+	// component numbers are arbitrary so compare the induced partitions
+	m1, m2 := make(map[int]int), make(map[int]int)
+	for i := 1; i < len(comp); i++ {
+		if c, ok := m1[comp[i]]; ok && c != expected[i] {
+			t.Fatalf("Got components %v expected %v", comp, expected)
+		}
+		if c, ok := m2[expected[i]]; ok && c != comp[i] {
+			t.Fatalf("Got components %v expected %v", comp, expected)
+		}
+		m1[comp[i]] = expected[i]
+		m2[expected[i]] = comp[i]
+	}
+}
+
+func TestComponents(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	g.AddVertices(4)
+	g.AddArc(1, 2, 0, 1, 0)
+	g.AddArc(2, 1, 0, 1, 0)
+	g.AddArc(4, 3, 0, 1, 0)
+	CheckComponents(t, g.WeakComponents(), []int{0, 1, 1, 2, 2})
+	CheckComponents(t, g.StrongComponents(), []int{0, 1, 1, 2, 3})
+}
+
+func TestTopSort(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	g.AddVertices(4)
+	g.AddArc(3, 1, 0, 1, 0)
+	g.AddArc(1, 2, 0, 1, 0)
+	g.AddArc(3, 4, 0, 1, 0)
+	g.AddArc(4, 2, 0, 1, 0)
+	num := g.TopSort()
+	for _, a := range g.Arcs() {
+		if num[a.Tail] == 0 || num[a.Tail] >= num[a.Head] {
+			t.Errorf("arc (%d, %d) violates ordering %v", a.Tail, a.Head, num)
+		}
+	}
+	g.AddArc(2, 3, 0, 1, 0) // creates a cycle
+	num = g.TopSort()
+	for i := 1; i <= 4; i++ {
+		if num[i] != 0 {
+			t.Errorf("vertex %d is in a cycle but got position %d", i, num[i])
+		}
+	}
+}
+
+func TestNetgen(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	params := NetgenParams{
+		Seed: 13502460, Problem: 101, Nodes: 200, Sources: 100,
+		Sinks: 100, Arcs: 1500, MinCost: 1, MaxCost: 100,
+		Supply: 400000, PctMaxCost: 30, PctCap: 100, MinCap: 400,
+		MaxCap: 800}
+	if err := g.Netgen(params); err != nil {
+		t.Fatal(err)
+	}
+	if n := g.NumVertices(); n != 200 {
+		t.Errorf("Got %d vertices expected 200", n)
+	}
+	if n := g.NumArcs(); n == 0 {
+		t.Error("Got no arcs")
+	}
+	supply := 0.0
+	for i := 1; i <= g.NumVertices(); i++ {
+		supply += g.VertexRhs(i)
+	}
+	if supply != 0 {
+		t.Errorf("Got total supply minus demand %g expected 0", supply)
+	}
+	params.Nodes = 0
+	if err := g.Netgen(params); err == nil {
+		t.Error("expected error for inconsistent parameters")
+	}
+}
+
+func TestGridgen(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	params := GridgenParams{
+		TwoWays: true, Seed: 123, Nodes: 100, Width: 10, Sources: 5,
+		Sinks: 5, AvgDegree: 4, Flow: 1000, CostDist: 1, MinCost: 1,
+		MaxCost: 100, CapDist: 1, MinCap: 100, MaxCap: 1000}
+	if err := g.Gridgen(params); err != nil {
+		t.Fatal(err)
+	}
+	if n := g.NumVertices(); n == 0 {
+		t.Error("Got no vertices")
+	}
+	if n := g.NumArcs(); n == 0 {
+		t.Error("Got no arcs")
+	}
+	params.CostDist = 3
+	if err := g.Gridgen(params); err == nil {
+		t.Error("expected error for inconsistent parameters")
+	}
+}
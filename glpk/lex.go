@@ -0,0 +1,163 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"errors"
+	"math"
+	"unsafe"
+)
+
+// #include <glpk.h>
+import "C"
+
+// ErrNotOptimal is returned by Prob.LexMinimize() if the problem has
+// no optimal solution to start from.
+var ErrNotOptimal = errors.New("solution is not optimal")
+
+// LexMinimize makes the optimal solution of the problem canonical by
+// choosing, among all optimal solutions, the lexicographically
+// smallest one with respect to the columns listed in order: first the
+// value of column order[0] is minimized, then (with that value fixed)
+// the value of column order[1], and so on. This is useful e.g. to get
+// reproducible solutions of problems which have many optimal
+// solutions.
+//
+// The problem must already be solved to optimality: with Intopt if it
+// has integer columns (see NumInt) and with Simplex otherwise, and the
+// same solver (with default parameters and the presolver enabled for
+// MIP) is used for the additional solves, so the cost is one extra
+// solve per element of order plus one final solve with the original
+// objective (which makes the reported solution consistent with it).
+// The optimal objective value is kept (up to a relative tolerance of
+// 1e-9) by a temporary constraint. The original objective, bounds, and
+// constraints are restored before returning.
+//
+// Returns ErrNotOptimal if the problem has no optimal solution, or an
+// error returned by the solver.
+func (p *Prob) LexMinimize(order []int) error {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	mip := p.NumInt() > 0
+	if mip && p.MipStatus() != OPT || !mip && p.Status() != OPT {
+		return ErrNotOptimal
+	}
+	objVal := p.ObjVal()
+	if mip {
+		objVal = p.MipObjVal()
+	}
+
+	// save the objective and the bounds to be restored
+	n := p.NumCols()
+	dir := p.ObjDir()
+	coef := make([]float64, n+1)
+	for j := 0; j <= n; j++ {
+		coef[j] = p.ObjCoef(j)
+	}
+	bnds := make([]ColInfo, len(order))
+	for k, j := range order {
+		bnds[k] = ColInfo{Type: p.ColType(j), LB: p.ColLB(j), UB: p.ColUB(j)}
+	}
+
+	// row keeping the objective at its optimal value
+	row := p.AddRows(1)
+	ind := make([]int32, 1, n+1)
+	val := make([]float64, 1, n+1)
+	for j := 1; j <= n; j++ {
+		if coef[j] != 0 {
+			ind = append(ind, int32(j))
+			val = append(val, coef[j])
+		}
+	}
+	p.SetMatRow(row, ind, val)
+	tol := 1e-9 * math.Max(1, math.Abs(objVal))
+	if dir == MAX {
+		p.SetRowBnds(row, LO, objVal-coef[0]-tol, 0)
+	} else {
+		p.SetRowBnds(row, UP, 0, objVal-coef[0]+tol)
+	}
+
+	defer func() {
+		num := []int32{0, int32(row)}
+		C.glp_del_rows(p.p.p, 1, (*C.int)(unsafe.Pointer(&num[0])))
+		for k, j := range order {
+			p.SetColBnds(j, bnds[k].Type, bnds[k].LB, bnds[k].UB)
+		}
+	}()
+
+	for j := 0; j <= n; j++ {
+		p.SetObjCoef(j, 0)
+	}
+	p.SetObjDir(MIN)
+	for _, j := range order {
+		p.SetObjCoef(j, 1)
+		if err := p.lexSolve(mip); err != nil {
+			p.restoreObj(dir, coef)
+			return err
+		}
+		p.SetObjCoef(j, 0)
+		v := p.ColPrim(j)
+		if mip {
+			v = p.MipColVal(j)
+		}
+		p.fixUB(j, v)
+	}
+	p.restoreObj(dir, coef)
+	return p.lexSolve(mip)
+}
+
+// lexSolve solves the problem for LexMinimize.
+func (p *Prob) lexSolve(mip bool) error {
+	if mip {
+		return p.Intopt(NewIocp(WithPresolve(true), WithMsgLev(MSG_ERR)))
+	}
+	return p.Simplex(NewSmcp(WithMsgLev(MSG_ERR)))
+}
+
+// restoreObj restores the objective saved by LexMinimize.
+func (p *Prob) restoreObj(dir ObjDir, coef []float64) {
+	p.SetObjDir(dir)
+	for j, c := range coef {
+		p.SetObjCoef(j, c)
+	}
+}
+
+// fixUB sets the upper bound of the j-th column to v keeping its
+// lower bound (if any).
+func (p *Prob) fixUB(j int, v float64) {
+	lb := p.ColLB(j)
+	switch p.ColType(j) {
+	case FR, UP:
+		p.SetColBnds(j, UP, 0, v)
+	default:
+		if v <= lb {
+			p.SetColBnds(j, FX, lb, lb)
+		} else {
+			p.SetColBnds(j, DB, lb, v)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "testing"
+
+// PrepareLexTestExample prepares problem: maximize x + y subject to
+// x + y <= 2, x, y >= 0 (which has many optimal solutions).
+func PrepareLexTestExample(kind VarType) *Prob {
+	lp := New()
+	lp.SetObjDir(MAX)
+	lp.AddRows(1)
+	lp.SetRowBnds(1, UP, 0, 2)
+	lp.AddCols(2)
+	for j := 1; j <= 2; j++ {
+		lp.SetColBnds(j, LO, 0, 0)
+		lp.SetColKind(j, kind)
+		lp.SetObjCoef(j, 1)
+	}
+	lp.SetMatRow(1, []int32{0, 1, 2}, []float64{0, 1, 1})
+	return lp
+}
+
+func TestLexMinimize(t *testing.T) {
+	lp := PrepareLexTestExample(CV)
+	defer lp.Delete()
+	if err := lp.LexMinimize([]int{1}); err != ErrNotOptimal {
+		t.Errorf("expected %v but got %v", ErrNotOptimal, err)
+	}
+	SolveOptimal(t, lp)
+	if err := lp.LexMinimize([]int{1, 2}); err != nil {
+		t.Fatalf("LexMinimize error: %v", err)
+	}
+	CheckClose(t, lp.ObjVal(), 2)
+	CheckClose(t, lp.ColPrim(1), 0)
+	CheckClose(t, lp.ColPrim(2), 2)
+	if err := lp.LexMinimize([]int{2}); err != nil {
+		t.Fatalf("LexMinimize error: %v", err)
+	}
+	CheckClose(t, lp.ColPrim(1), 2)
+	CheckClose(t, lp.ColPrim(2), 0)
+	if n := lp.NumRows(); n != 1 {
+		t.Errorf("expected 1 row but got %d", n)
+	}
+	if typ := lp.ColType(1); typ != LO {
+		t.Errorf("expected restored bounds type %d but got %d", LO, typ)
+	}
+	if d := lp.ObjDir(); d != MAX {
+		t.Errorf("expected restored direction %d but got %d", MAX, d)
+	}
+}
+
+func TestLexMinimizeMip(t *testing.T) {
+	lp := PrepareLexTestExample(IV)
+	defer lp.Delete()
+	if err := lp.Intopt(NewIocp(WithPresolve(true), WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	if err := lp.LexMinimize([]int{1}); err != nil {
+		t.Fatalf("LexMinimize error: %v", err)
+	}
+	CheckClose(t, lp.MipObjVal(), 2)
+	CheckClose(t, lp.MipColVal(1), 0)
+	CheckClose(t, lp.MipColVal(2), 2)
+}
+
+// SolveOptimal solves the problem with Simplex and checks
+// that the optimal solution was found.
+func SolveOptimal(t *testing.T, lp *Prob) {
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if st := lp.Status(); st != OPT {
+		t.Fatalf("expected optimal solution but got %d", st)
+	}
+}
@@ -0,0 +1,97 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// mpsSensePrefix starts the MPS comment line recording the objective
+// function direction (see WriteMPSWithSense).
+const mpsSensePrefix = "* OBJSENSE "
+
+// WriteMPSWithSense writes the problem instance into a file in MPS
+// file format (just as WriteMPS does) and additionally records the
+// objective function direction in a comment line "* OBJSENSE MAX" (or
+// "* OBJSENSE MIN") at the beginning of the file. The file remains a
+// valid MPS file (comment lines are ignored by MPS readers) and the
+// direction is restored by ReadMPSWithSense.
+func (p *Prob) WriteMPSWithSense(format MPSFormat, params *MPSCP, filename string) error {
+	if err := p.WriteMPS(format, params, filename); err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return &PathError{"write", filename, err.Error()}
+	}
+	sense := "MIN"
+	if p.ObjDir() == MAX {
+		sense = "MAX"
+	}
+	data = append([]byte(mpsSensePrefix+sense+"\n"), data...)
+	if err := ioutil.WriteFile(filename, data, 0666); err != nil {
+		return &PathError{"write", filename, err.Error()}
+	}
+	return nil
+}
+
+// ReadMPSWithSense reads the problem instance from a file in MPS file
+// format (just as ReadMPS does) and sets the objective function
+// direction recorded by WriteMPSWithSense. If the file does not
+// record the direction minimization is assumed (as in ReadMPS).
+func (p *Prob) ReadMPSWithSense(format MPSFormat, params *MPSCP, filename string) error {
+	if err := p.ReadMPS(format, params, filename); err != nil {
+		return err
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return &PathError{"read", filename, err.Error()}
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if !strings.HasPrefix(line, "*") {
+			break // the direction is recorded before any MPS data
+		}
+		if strings.HasPrefix(line, mpsSensePrefix) {
+			switch strings.TrimSpace(line[len(mpsSensePrefix):]) {
+			case "MAX":
+				p.SetObjDir(MAX)
+			case "MIN":
+				p.SetObjDir(MIN)
+			}
+			break
+		}
+	}
+	if err := s.Err(); err != nil {
+		return &PathError{"read", filename, err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadWriteMPSWithSense(t *testing.T) {
+	lp := PrepareTestExample(t)
+	f, err := ioutil.TempFile("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	for _, format := range []MPSFormat{MPS_DECK, MPS_FILE} {
+		if err := lp.WriteMPSWithSense(format, nil, f.Name()); err != nil {
+			t.Fatal(err)
+		}
+		lp1 := New()
+		if err := lp1.ReadMPS(format, nil, f.Name()); err != nil {
+			t.Error(err)
+		} else if d := lp1.ObjDir(); d != MIN {
+			t.Errorf("expected ReadMPS to ignore direction but got %d", d)
+		}
+		if err := lp1.ReadMPSWithSense(format, nil, f.Name()); err != nil {
+			t.Error(err)
+		} else {
+			if d := lp1.ObjDir(); d != MAX {
+				t.Errorf("expected direction %d but got %d", MAX, d)
+			}
+			CheckSimplexSolution(t, lp1)
+		}
+		lp1.Delete()
+	}
+	lp.Delete()
+}
+
+func TestWriteFile(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	dir, err := ioutil.TempDir("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, ext := range []string{".mps", ".LP", ".glpk"} {
+		name := filepath.Join(dir, "prob"+ext)
+		if err := lp.WriteFile(name); err != nil {
+			t.Errorf("WriteFile error: %v", err)
+			continue
+		}
+		lp1 := New()
+		switch ext {
+		case ".mps":
+			err = lp1.ReadMPS(MPS_FILE, nil, name)
+			lp1.SetObjDir(MAX)
+		case ".LP":
+			err = lp1.ReadLP(nil, name)
+		case ".glpk":
+			err = lp1.ReadProb(0, name)
+		}
+		if err != nil {
+			t.Errorf("error reading %s: %v", name, err)
+		} else {
+			CheckSimplexSolution(t, lp1)
+		}
+		lp1.Delete()
+	}
+	if _, ok := lp.WriteFile(filepath.Join(dir, "prob.txt")).(*PathError); !ok {
+		t.Error("expected PathError for unknown extension")
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	dir, err := ioutil.TempDir("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, ext := range []string{".mps", ".lp", ".glpk"} {
+		name := filepath.Join(dir, "prob"+ext)
+		if err := lp.WriteFile(name); err != nil {
+			t.Fatalf("WriteFile error: %v", err)
+		}
+		// the same content without a known extension
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sniffed := filepath.Join(dir, "prob"+ext+".dat")
+		if err := ioutil.WriteFile(sniffed, data, 0666); err != nil {
+			t.Fatal(err)
+		}
+		for _, fname := range []string{name, sniffed} {
+			lp1 := New()
+			if err := lp1.ReadFile(fname); err != nil {
+				t.Errorf("ReadFile error: %v", err)
+			} else {
+				lp1.SetObjDir(MAX) // not stored in MPS format
+				CheckSimplexSolution(t, lp1)
+			}
+			lp1.Delete()
+		}
+	}
+	unknown := filepath.Join(dir, "prob.txt")
+	if err := ioutil.WriteFile(unknown, []byte("hello\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lp.ReadFile(unknown).(*PathError); !ok {
+		t.Error("expected PathError for unknown file format")
+	}
+}
+
+func TestReadAuto(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	dir, err := ioutil.TempDir("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, ext := range []string{".mps", ".lp", ".glpk"} {
+		name := filepath.Join(dir, "prob"+ext)
+		if err := lp.WriteFile(name); err != nil {
+			t.Fatalf("WriteFile error: %v", err)
+		}
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lp1 := New()
+		if err := lp1.ReadAuto(bytes.NewReader(data)); err != nil {
+			t.Errorf("ReadAuto error (%s): %v", ext, err)
+		} else {
+			lp1.SetObjDir(MAX) // not stored in MPS format
+			CheckSimplexSolution(t, lp1)
+		}
+		lp1.Delete()
+	}
+	if err := lp.ReadAuto(strings.NewReader("hello\n")); err != ErrUnknownFormat {
+		t.Errorf("expected ErrUnknownFormat but got %v", err)
+	}
+}
+
+func TestSetObjRowName(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	lp.SetObjRowName("cost")
+	if name := lp.ObjName(); name != "cost" {
+		t.Errorf("expected cost but got %q", name)
+	}
+	dir, err := ioutil.TempDir("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "prob.mps")
+	if err := lp.WriteMPS(MPS_FILE, nil, name); err != nil {
+		t.Fatalf("WriteMPS error: %v", err)
+	}
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if f := strings.Fields(line); len(f) == 2 && f[0] == "N" && f[1] == "cost" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected objective row cost in:\n%s", data)
+	}
+	lp1 := New()
+	defer lp1.Delete()
+	if err := lp1.ReadMPS(MPS_FILE, nil, name); err != nil {
+		t.Fatalf("ReadMPS error: %v", err)
+	}
+	if name := lp1.ObjName(); name != "cost" {
+		t.Errorf("expected cost but got %q", name)
+	}
+}
@@ -0,0 +1,146 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "io"
+
+// SmcpOption is an option of NewSmcp().
+type SmcpOption interface {
+	applySmcp(s *Smcp)
+}
+
+// IocpOption is an option of NewIocp().
+type IocpOption interface {
+	applyIocp(p *Iocp)
+}
+
+// Option is an option which may be given both to NewSmcp() and to
+// NewIocp().
+type Option interface {
+	SmcpOption
+	IocpOption
+}
+
+type smcpOption func(s *Smcp)
+
+func (f smcpOption) applySmcp(s *Smcp) { f(s) }
+
+type iocpOption func(p *Iocp)
+
+func (f iocpOption) applyIocp(p *Iocp) { f(p) }
+
+type option struct {
+	smcp smcpOption
+	iocp iocpOption
+}
+
+func (o option) applySmcp(s *Smcp) { o.smcp(s) }
+func (o option) applyIocp(p *Iocp) { o.iocp(p) }
+
+// WithMsgLev sets message level (see Smcp.SetMsgLev() and
+// Iocp.SetMsgLev()).
+func WithMsgLev(lev MsgLev) Option {
+	return option{
+		func(s *Smcp) { s.SetMsgLev(lev) },
+		func(p *Iocp) { p.SetMsgLev(lev) },
+	}
+}
+
+// WithTmLim sets searching time limit in milliseconds (see
+// Smcp.SetTmLim() and Iocp.SetTmLim()).
+func WithTmLim(tmLim int) Option {
+	return option{
+		func(s *Smcp) { s.SetTmLim(tmLim) },
+		func(p *Iocp) { p.SetTmLim(tmLim) },
+	}
+}
+
+// WithPresolve enables or disables the presolver (see
+// Smcp.SetPresolve() and Iocp.SetPresolve()).
+func WithPresolve(on bool) Option {
+	return option{
+		func(s *Smcp) { s.SetPresolve(on) },
+		func(p *Iocp) { p.SetPresolve(on) },
+	}
+}
+
+// WithOutput redirects the GLPK terminal output of the solver to w
+// (see Smcp.SetOutput() and Iocp.SetOutput()).
+func WithOutput(w io.Writer) Option {
+	return option{
+		func(s *Smcp) { s.SetOutput(w) },
+		func(p *Iocp) { p.SetOutput(w) },
+	}
+}
+
+// WithMeth sets simplex method option (see Smcp.SetMeth()).
+func WithMeth(meth Meth) SmcpOption {
+	return smcpOption(func(s *Smcp) { s.SetMeth(meth) })
+}
+
+// WithPricing sets pricing technique (see Smcp.SetPricing()).
+func WithPricing(pricing Pricing) SmcpOption {
+	return smcpOption(func(s *Smcp) { s.SetPricing(pricing) })
+}
+
+// WithRTest sets ratio test technique (see Smcp.SetRTest()).
+func WithRTest(rTest RTest) SmcpOption {
+	return smcpOption(func(s *Smcp) { s.SetRTest(rTest) })
+}
+
+// WithItLim sets simplex iteration limit (see Smcp.SetItLim()).
+func WithItLim(itLim int) SmcpOption {
+	return smcpOption(func(s *Smcp) { s.SetItLim(itLim) })
+}
+
+// WithObjLimits sets the limits of the objective function for the
+// dual simplex (see Smcp.SetObjLimits()).
+func WithObjLimits(ll, ul float64) SmcpOption {
+	return smcpOption(func(s *Smcp) { s.SetObjLimits(ll, ul) })
+}
+
+// WithNodeLim sets the limit on the number of subproblems (see
+// Iocp.SetNodeLim()).
+func WithNodeLim(nodeLim int) IocpOption {
+	return iocpOption(func(p *Iocp) { p.SetNodeLim(nodeLim) })
+}
+
+// WithCallback sets the branch-and-cut callback (see
+// Iocp.SetCallback()).
+func WithCallback(callback func(t *Tree)) IocpOption {
+	return iocpOption(func(p *Iocp) { p.SetCallback(callback) })
+}
+
+// WithInitialSolution sets the initial integer feasible solution (see
+// Iocp.SetInitialSolution()).
+func WithInitialSolution(x []float64) IocpOption {
+	return iocpOption(func(p *Iocp) { p.SetInitialSolution(x) })
+}
+
+// WithHeuristic sets the primal heuristic (see Iocp.SetHeuristic()).
+func WithHeuristic(h func(relaxation []float64) ([]float64, bool)) IocpOption {
+	return iocpOption(func(p *Iocp) { p.SetHeuristic(h) })
+}
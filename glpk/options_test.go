@@ -0,0 +1,98 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	smcp := NewSmcp(WithPresolve(true), WithMeth(DUAL), WithMsgLev(MSG_ERR))
+	if !smcp.Presolve() {
+		t.Error("expected presolver enabled by WithPresolve")
+	}
+	iocp := NewIocp(WithPresolve(true), WithNodeLim(10), WithTmLim(5000))
+	if !iocp.Presolve() {
+		t.Error("expected presolver enabled by WithPresolve")
+	}
+	if iocp.nodeLim != 10 {
+		t.Errorf("expected node limit 10 but got %d", iocp.nodeLim)
+	}
+	if NewSmcp().Presolve() || NewIocp().Presolve() {
+		t.Error("expected presolver disabled by default")
+	}
+
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if err := lp.Simplex(smcp); err != nil {
+		t.Errorf("Simplex error: %v", err)
+	}
+	CheckSolution(t, lp)
+
+	// the objective of the dual simplex decreases to the optimum
+	// 733.33 (maximization) so it falls below the lower limit
+	lp = PrepareTestExample(t)
+	defer lp.Delete()
+	smcp = NewSmcp(WithMeth(DUAL), WithMsgLev(MSG_ERR), WithObjLimits(800, math.MaxFloat64))
+	if err := lp.Simplex(smcp); err != EOBJLL {
+		t.Errorf("expected EOBJLL but got %v", err)
+	}
+}
+
+func TestWithOutput(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	var buf bytes.Buffer
+	if err := lp.Simplex(NewSmcp(WithOutput(&buf), WithMsgLev(MSG_ALL))); err != nil {
+		t.Errorf("Simplex error: %v", err)
+	}
+	CheckSolution(t, lp)
+	if buf.Len() == 0 {
+		t.Error("expected simplex output to be captured")
+	}
+
+	lp = PrepareMipTestExample(t)
+	defer lp.Delete()
+	buf.Reset()
+	if err := lp.Intopt(NewIocp(WithOutput(&buf), WithPresolve(true), WithMsgLev(MSG_ALL))); err != nil {
+		t.Errorf("Mip error: %v", err)
+	}
+	CheckMipSolution(t, lp)
+	if buf.Len() == 0 {
+		t.Error("expected branch-and-cut output to be captured")
+	}
+}
+
+func TestObjLimits(t *testing.T) {
+	smcp := NewSmcp()
+	if smcp.smcp.obj_ll != -math.MaxFloat64 || smcp.smcp.obj_ul != math.MaxFloat64 {
+		t.Errorf("expected no limits by default but got %g and %g", smcp.smcp.obj_ll, smcp.smcp.obj_ul)
+	}
+	smcp = NewSmcp(WithMeth(DUAL), WithMsgLev(MSG_ERR), WithObjLimits(0, 1000))
+	if smcp.smcp.obj_ll != 0 || smcp.smcp.obj_ul != 1000 {
+		t.Errorf("expected limits 0 and 1000 but got %g and %g", smcp.smcp.obj_ll, smcp.smcp.obj_ul)
+	}
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if err := lp.Simplex(smcp); err != nil {
+		t.Errorf("Simplex error: %v", err)
+	}
+	CheckSolution(t, lp)
+}
@@ -0,0 +1,89 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+// AddPiecewiseLinear adds a new column y = f(x), where x is the j-th
+// column and f is the piecewise linear function with f(breakpoints[k])
+// = values[k] (linear between consecutive breakpoints), and returns
+// the number of the new column. It uses the lambda formulation: it
+// adds a column lambda[k] >= 0 for each breakpoint and the rows
+//
+//	sum lambda[k] = 1
+//	x = sum breakpoints[k]*lambda[k]
+//	y = sum values[k]*lambda[k]
+//
+// The formulation is exact (without integer variables) only if the
+// solver has no incentive to use non-adjacent breakpoints, i.e. if f
+// is convex and y is minimized (or y has a positive objective
+// coefficient in a minimization problem), or f is concave and y is
+// maximized. Otherwise y may be anywhere between f(x) and the convex
+// (or concave) envelope of f. Also x is restricted to
+// [breakpoints[0], breakpoints[len(breakpoints)-1]].
+//
+// Requires at least two breakpoints in increasing order and
+// len(values) = len(breakpoints).
+func (p *Prob) AddPiecewiseLinear(j int, breakpoints, values []float64) int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	if len(breakpoints) != len(values) {
+		panic("len(breakpoints) and len(values) should be equal")
+	}
+	if len(breakpoints) < 2 {
+		panic("at least two breakpoints are required")
+	}
+	for k := 1; k < len(breakpoints); k++ {
+		if breakpoints[k-1] >= breakpoints[k] {
+			panic("breakpoints should be in increasing order")
+		}
+	}
+	n := len(breakpoints)
+	y := p.AddCols(n + 1)
+	p.SetColBnds(y, FR, 0, 0)
+	convInd := make([]int32, n+1)
+	convVal := make([]float64, n+1)
+	xInd := make([]int32, n+2)
+	xVal := make([]float64, n+2)
+	yInd := make([]int32, n+2)
+	yVal := make([]float64, n+2)
+	for k := 0; k < n; k++ {
+		lambda := int32(y + 1 + k)
+		p.SetColBnds(int(lambda), LO, 0, 0)
+		convInd[k+1], convVal[k+1] = lambda, 1
+		xInd[k+1], xVal[k+1] = lambda, -breakpoints[k]
+		yInd[k+1], yVal[k+1] = lambda, -values[k]
+	}
+	xInd[n+1], xVal[n+1] = int32(j), 1
+	yInd[n+1], yVal[n+1] = int32(y), 1
+	i := p.AddRows(3)
+	p.SetRowBnds(i, FX, 1, 1)
+	p.SetMatRow(i, convInd, convVal)
+	p.SetRowBnds(i+1, FX, 0, 0)
+	p.SetMatRow(i+1, xInd, xVal)
+	p.SetRowBnds(i+2, FX, 0, 0)
+	p.SetMatRow(i+2, yInd, yVal)
+	return y
+}
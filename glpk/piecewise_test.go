@@ -0,0 +1,37 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "testing"
+
+func TestAddPiecewiseLinear(t *testing.T) {
+	// minimize y = f(x) = 2|x - 1| for x in [0, 2]
+	lp := New()
+	defer lp.Delete()
+	x := lp.AddCols(1)
+	lp.SetColBnds(x, DB, 0, 2)
+	y := lp.AddPiecewiseLinear(x, []float64{0, 1, 2}, []float64{2, 0, 2})
+	lp.SetObjCoef(y, 1)
+	SolveOptimal(t, lp)
+	CheckClose(t, lp.ColPrim(x), 1)
+	CheckClose(t, lp.ColPrim(y), 0)
+
+	lp.SetColBnds(x, FX, 0.5, 0.5)
+	SolveOptimal(t, lp)
+	CheckClose(t, lp.ColPrim(y), 1)
+}
@@ -0,0 +1,98 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "math"
+
+// RemoveRedundantRows deletes (see DelRows) rows which are redundant,
+// i.e. which can never be binding given the other constraints and the
+// bounds of the columns, and returns the number of the deleted rows.
+// A bound of a row is redundant if the linear form of the row can not
+// exceed it (up to a relative tolerance of 1e-9) within the feasible
+// region of the remaining rows, which is checked by maximizing (or
+// minimizing) the linear form with Prob.Simplex(), so the cost is one
+// or two solves per row and may be high for large problems. Rows
+// found redundant are left out when checking the subsequent rows, so
+// removing all of them does not change the feasible region. Fixed and
+// free rows are never deleted and integrality of the columns is
+// ignored (so it is a heuristic for MIP: a row redundant for the LP
+// relaxation is also redundant for the MIP but not necessarily vice
+// versa). Nothing is deleted if the problem is infeasible.
+func (p *Prob) RemoveRedundantRows() int {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	q := p.Copy(false)
+	defer q.Delete()
+	n := q.NumCols()
+	js := make([]int, n+1)
+	for j := range js {
+		js[j] = j
+	}
+	q.SetObjCoefs(js, make([]float64, n+1))
+	smcp := NewSmcp(WithMsgLev(MSG_OFF))
+	if ok, err := q.FindFeasible(smcp); err != nil || !ok {
+		return 0
+	}
+
+	// bndOK checks whether the linear form of the row, set as the
+	// objective, can not exceed bnd when optimized in direction dir
+	bndOK := func(dir ObjDir, bnd float64) bool {
+		q.SetObjDir(dir)
+		if q.Simplex(smcp) != nil || q.Status() != OPT {
+			return false
+		}
+		tol := 1e-9 * math.Max(1, math.Abs(bnd))
+		if dir == MAX {
+			return q.ObjVal() <= bnd+tol
+		}
+		return q.ObjVal() >= bnd-tol
+	}
+	num := []int32{0}
+	m := q.NumRows()
+	for i := 1; i <= m; i++ {
+		typ, lb, ub := q.RowType(i), q.RowLB(i), q.RowUB(i)
+		if typ == FR || typ == FX {
+			continue
+		}
+		ind, val := q.MatRow(i)
+		q.SetRowBnds(i, FR, 0, 0)
+		for k := 1; k < len(ind); k++ {
+			q.SetObjCoef(int(ind[k]), val[k])
+		}
+		redundant := (typ == LO || bndOK(MAX, ub)) && (typ == UP || bndOK(MIN, lb))
+		for k := 1; k < len(ind); k++ {
+			q.SetObjCoef(int(ind[k]), 0)
+		}
+		if redundant {
+			num = append(num, int32(i))
+		} else {
+			q.SetRowBnds(i, typ, lb, ub)
+		}
+	}
+	p.DelRows(num)
+	return len(num) - 1
+}
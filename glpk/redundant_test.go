@@ -0,0 +1,46 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "testing"
+
+func TestRemoveRedundantRows(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	// s: x0 + x1 + x2 <= 200 (implied by p)
+	// u: 0 <= x0 <= 70 (implied by q: 10 x0 <= 600)
+	i := lp.AddRows(2)
+	lp.SetRowName(i, "s")
+	lp.SetRowBnds(i, UP, 0, 200)
+	lp.SetMatRow(i, []int32{0, 1, 2, 3}, []float64{0, 1, 1, 1})
+	lp.SetRowName(i+1, "u")
+	lp.SetRowBnds(i+1, DB, 0, 70)
+	lp.SetMatRow(i+1, []int32{0, 1}, []float64{0, 1})
+	if n := lp.RemoveRedundantRows(); n != 2 {
+		t.Errorf("expected 2 redundant rows but got %d", n)
+	}
+	if m := lp.NumRows(); m != 3 {
+		t.Fatalf("expected 3 rows but got %d", m)
+	}
+	for i, name := range []string{"p", "q", "r"} {
+		if s := lp.RowName(i + 1); s != name {
+			t.Errorf("expected row %s but got %s", name, s)
+		}
+	}
+	CheckSimplexSolution(t, lp)
+}
@@ -0,0 +1,161 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+)
+
+// WriteReport writes a human-readable text report of the problem and
+// of its solution to w, e.g. for archiving: the problem size, the
+// objective (see ObjString), the constraints (see RowString), the
+// bounds and kinds of the columns, and the solution. The solution is
+// the MIP solution if the problem has integer columns and a MIP
+// solution was found (see ColValue), with the values of the rows and
+// of the columns, and otherwise the basic solution, also with the dual
+// values of the rows and the reduced costs of the columns. If the
+// problem has not been solved (the status of the solution is UNDEF)
+// only its status is reported. Unnamed rows are shown as r1, r2, etc.
+// and unnamed columns as x1, x2, etc.
+func (p *Prob) WriteReport(w io.Writer) error {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	var buf bytes.Buffer
+	m, n := p.NumRows(), p.NumCols()
+	if name := p.ProbName(); name != "" {
+		buf.WriteString("Problem:     " + name + "\n")
+	}
+	buf.WriteString("Rows:        " + strconv.Itoa(m) + "\n")
+	buf.WriteString("Columns:     " + strconv.Itoa(n) + " (" + strconv.Itoa(p.NumInt()) + " integer)\n")
+	buf.WriteString("Non-zeros:   " + strconv.Itoa(p.NumNz()) + "\n")
+
+	buf.WriteString("\nObjective:\n  " + p.ObjString() + "\n")
+	if m > 0 {
+		buf.WriteString("\nConstraints:\n")
+	}
+	for i := 1; i <= m; i++ {
+		buf.WriteString("  " + p.RowString(i) + "\n")
+	}
+	if n > 0 {
+		buf.WriteString("\nBounds:\n")
+	}
+	for j := 1; j <= n; j++ {
+		name := p.reportColName(j)
+		lb, ub := formatCoef(p.ColLB(j)), formatCoef(p.ColUB(j))
+		switch p.ColType(j) {
+		case FR:
+			buf.WriteString("  " + name + " free")
+		case LO:
+			buf.WriteString("  " + name + " >= " + lb)
+		case UP:
+			buf.WriteString("  " + name + " <= " + ub)
+		case DB:
+			buf.WriteString("  " + lb + " <= " + name + " <= " + ub)
+		case FX:
+			buf.WriteString("  " + name + " = " + lb)
+		}
+		switch p.ColKind(j) {
+		case IV:
+			buf.WriteString(", integer")
+		case BV:
+			buf.WriteString(", binary")
+		}
+		buf.WriteString("\n")
+	}
+
+	mip := false
+	if p.NumInt() > 0 {
+		st := p.MipStatus()
+		mip = st == OPT || st == FEAS
+	}
+	if mip {
+		buf.WriteString("\nSolution:    MIP, " + solStatName(p.MipStatus()) + "\n")
+		buf.WriteString("Objective:   " + formatCoef(p.MipObjVal()) + "\n")
+		buf.WriteString("\nRows:\n")
+		for i := 1; i <= m; i++ {
+			buf.WriteString("  " + p.reportRowName(i) + " = " + formatCoef(p.MipRowVal(i)) + "\n")
+		}
+		buf.WriteString("\nColumns:\n")
+		for j := 1; j <= n; j++ {
+			buf.WriteString("  " + p.reportColName(j) + " = " + formatCoef(p.MipColVal(j)) + "\n")
+		}
+	} else if st := p.Status(); st == UNDEF {
+		buf.WriteString("\nSolution:    " + solStatName(st) + "\n")
+	} else {
+		buf.WriteString("\nSolution:    basic, " + solStatName(st) + "\n")
+		buf.WriteString("Objective:   " + formatCoef(p.ObjVal()) + "\n")
+		buf.WriteString("\nRows:\n")
+		for i := 1; i <= m; i++ {
+			buf.WriteString("  " + p.reportRowName(i) + " = " + formatCoef(p.RowPrim(i)) + ", dual " + formatCoef(p.RowDual(i)) + "\n")
+		}
+		buf.WriteString("\nColumns:\n")
+		for j := 1; j <= n; j++ {
+			buf.WriteString("  " + p.reportColName(j) + " = " + formatCoef(p.ColPrim(j)) + ", reduced cost " + formatCoef(p.ColDual(j)) + "\n")
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// reportRowName returns the name of the i-th row or r<i> if it has no
+// name.
+func (p *Prob) reportRowName(i int) string {
+	if name := p.RowName(i); name != "" {
+		return name
+	}
+	return "r" + strconv.Itoa(i)
+}
+
+// reportColName returns the name of the j-th column or x<j> if it has
+// no name.
+func (p *Prob) reportColName(j int) string {
+	if name := p.ColName(j); name != "" {
+		return name
+	}
+	return "x" + strconv.Itoa(j)
+}
+
+// solStatName returns a description of the solution status.
+func solStatName(st SolStat) string {
+	switch st {
+	case UNDEF:
+		return "undefined"
+	case FEAS:
+		return "feasible"
+	case INFEAS:
+		return "infeasible"
+	case NOFEAS:
+		return "no feasible solution"
+	case OPT:
+		return "optimal"
+	case UNBND:
+		return "unbounded"
+	}
+	return "unknown"
+}
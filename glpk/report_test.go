@@ -0,0 +1,84 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteReport(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	var buf bytes.Buffer
+	if err := lp.WriteReport(&buf); err != nil {
+		t.Fatalf("WriteReport error: %v", err)
+	}
+	for _, s := range []string{
+		"Rows:        3\n",
+		"  maximize Z: 10 x0 + 6 x1 + 4 x2\n",
+		"  p: x0 + x1 + x2 <= 100\n",
+		"  x0 >= 0\n",
+		"Solution:    undefined\n",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expected %q in report:\n%s", s, buf.String())
+		}
+	}
+	CheckSimplexSolution(t, lp)
+	buf.Reset()
+	if err := lp.WriteReport(&buf); err != nil {
+		t.Fatalf("WriteReport error: %v", err)
+	}
+	for _, s := range []string{
+		"Solution:    basic, optimal\n",
+		"  r = ",
+		"  x2 = 0, reduced cost ",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expected %q in report:\n%s", s, buf.String())
+		}
+	}
+}
+
+func TestWriteReportMip(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	iocp.SetMsgLev(MSG_ERR)
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := lp.WriteReport(&buf); err != nil {
+		t.Fatalf("WriteReport error: %v", err)
+	}
+	for _, s := range []string{
+		"Columns:     4 (1 integer)\n",
+		"  2 <= x4 <= 3, integer\n",
+		"Solution:    MIP, optimal\n",
+		"Objective:   122.5\n",
+		"  x4 = 3\n",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expected %q in report:\n%s", s, buf.String())
+		}
+	}
+}
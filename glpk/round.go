@@ -0,0 +1,98 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoRepair is returned by Prob.RoundAndRepair() if there is no
+// feasible solution with the integer columns fixed at the rounded
+// values.
+var ErrNoRepair = errors.New("rounded solution cannot be repaired")
+
+// RoundAndRepair is a simple rounding heuristic. It rounds the values
+// of the integer columns of the current solution (as returned by
+// ColValue, e.g. a fractional solution of the LP relaxation or the
+// incumbent found before a time-out) to the nearest integers within
+// the bounds of the columns, fixes the integer columns at these
+// values, and solves the residual LP over the continuous columns with
+// Prob.Simplex() to restore feasibility. The bounds of the integer
+// columns are restored before returning, but the basic solution of
+// the problem is the solution of the residual LP.
+//
+// It returns the repaired solution in the format of MipSolutions():
+// sol[0] is the value of the objective function and sol[1]..sol[n]
+// are values of the columns (if the residual LP is unbounded the
+// solution is feasible but not optimal for it). Returns ErrNoRepair
+// if the residual LP has no feasible solution, or an error returned
+// by Prob.Simplex().
+func (p *Prob) RoundAndRepair() ([]float64, error) {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	n := p.NumCols()
+	var fixed []int
+	var bnds []ColInfo
+	for j := 1; j <= n; j++ {
+		if p.ColKind(j) == CV {
+			continue
+		}
+		typ, lb, ub := p.ColType(j), p.ColLB(j), p.ColUB(j)
+		v := math.Floor(p.ColValue(j) + 0.5)
+		if (typ == LO || typ == DB || typ == FX) && v < lb {
+			v = math.Ceil(lb)
+		}
+		if (typ == UP || typ == DB || typ == FX) && v > ub {
+			v = math.Floor(ub)
+		}
+		fixed = append(fixed, j)
+		bnds = append(bnds, ColInfo{Type: typ, LB: lb, UB: ub})
+		p.SetColBnds(j, FX, v, v)
+	}
+	defer func() {
+		for k, j := range fixed {
+			p.SetColBnds(j, bnds[k].Type, bnds[k].LB, bnds[k].UB)
+		}
+	}()
+
+	if err := p.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		if err == ENOPFS {
+			return nil, ErrNoRepair
+		}
+		return nil, err
+	}
+	if p.PrimStat() != FEAS {
+		return nil, ErrNoRepair
+	}
+	sol := make([]float64, n+1)
+	sol[0] = p.ObjVal()
+	for j := 1; j <= n; j++ {
+		sol[j] = p.ColPrim(j)
+	}
+	return sol, nil
+}
@@ -0,0 +1,62 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "testing"
+
+func TestRoundAndRepair(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	lp.SetColBnds(4, DB, 2.0, 2.6)
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if v := lp.ColPrim(4); v <= 2.5 {
+		t.Fatalf("expected x4 of the relaxation to round up but got %g", v)
+	}
+	sol, err := lp.RoundAndRepair()
+	if err != nil {
+		t.Fatalf("RoundAndRepair error: %v", err)
+	}
+	if len(sol) != lp.NumCols()+1 {
+		t.Fatalf("expected %d values but got %d", lp.NumCols()+1, len(sol))
+	}
+	// x4 can not be rounded up to 3 (above its upper bound)
+	CheckClose(t, sol[4], 2)
+	CheckClose(t, sol[2], 7) // as x2 = 3.5 x4
+	CheckClose(t, sol[0], lp.ObjVal())
+	if typ, lb, ub := lp.ColType(4), lp.ColLB(4), lp.ColUB(4); typ != DB || lb != 2 || ub != 2.6 {
+		t.Errorf("expected bounds of x4 to be restored but got %v %g %g", typ, lb, ub)
+	}
+}
+
+func TestRoundAndRepairInfeasible(t *testing.T) {
+	// x1 + x2 = 1.5, 0 <= x1 <= 1 integer, 0 <= x2 <= 0.2
+	lp := New()
+	defer lp.Delete()
+	lp.AddRows(1)
+	lp.SetRowBnds(1, FX, 1.5, 1.5)
+	lp.AddCols(2)
+	lp.SetColBnds(1, DB, 0, 1)
+	lp.SetColKind(1, IV)
+	lp.SetColBnds(2, DB, 0, 0.2)
+	lp.SetMatRow(1, []int32{0, 1, 2}, []float64{0, 1, 1})
+	if _, err := lp.RoundAndRepair(); err != ErrNoRepair {
+		t.Errorf("expected ErrNoRepair but got %v", err)
+	}
+}
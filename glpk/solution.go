@@ -0,0 +1,218 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ErrInvalidSolution is returned by Prob.Intopt() if the initial
+// solution set with Iocp.SetInitialSolution() is not integer feasible.
+var ErrInvalidSolution = errors.New("solution is not integer feasible")
+
+// feasTol is the relative tolerance used by Prob.isFeasible().
+const feasTol = 1e-9
+
+// isFeasible checks whether x[1]..x[n] (values of the columns) is an
+// integer feasible solution of the problem.
+func (p *Prob) isFeasible(x []float64) bool {
+	n := p.NumCols()
+	if len(x) != n+1 {
+		return false
+	}
+	for j := 1; j <= n; j++ {
+		if p.ColKind(j) != CV && math.Abs(x[j]-math.Floor(x[j]+0.5)) > feasTol {
+			return false
+		}
+	}
+	ok, _ := p.CheckFeasible(x, feasTol)
+	return ok
+}
+
+// roundInt returns a copy of x with the values of the integer columns
+// rounded to the nearest integer, as glp_ios_heur_sol() requires exact
+// integer values (isFeasible() accepts values within feasTol).
+func (p *Prob) roundInt(x []float64) []float64 {
+	y := make([]float64, len(x))
+	copy(y, x)
+	for j := 1; j < len(y); j++ {
+		if p.ColKind(j) != CV {
+			y[j] = math.Floor(y[j] + 0.5)
+		}
+	}
+	return y
+}
+
+// CheckFeasible checks whether x[1]..x[n] (values of the columns, x[0]
+// is ignored) satisfy the bounds of the columns and of the rows, e.g.
+// for a solution found by another solver or by a heuristic.
+// Integrality of the columns is not checked. The activities of the
+// rows are computed from the constraint matrix. A bound b is violated
+// if it is exceeded by more than eps*max(1, |b|). It returns whether x
+// is feasible and the numbers of the rows whose bounds are violated.
+// Requires len(x) = NumCols()+1.
+func (p *Prob) CheckFeasible(x []float64, eps float64) (bool, []int) {
+	n := p.NumCols()
+	if len(x) != n+1 {
+		panic("len(x) should be equal to the number of columns plus one")
+	}
+	ok := true
+	for j := 1; j <= n; j++ {
+		if !inBnds(x[j], p.ColType(j), p.ColLB(j), p.ColUB(j), eps) {
+			ok = false
+		}
+	}
+	row := make([]float64, p.NumRows()+1)
+	p.ForEachNonzero(func(i, j int, v float64) {
+		row[i] += v * x[j]
+	})
+	var violated []int
+	for i := 1; i < len(row); i++ {
+		if !inBnds(row[i], p.RowType(i), p.RowLB(i), p.RowUB(i), eps) {
+			violated = append(violated, i)
+		}
+	}
+	return ok && violated == nil, violated
+}
+
+// PrimalInfeasibilityCount returns the number of rows and columns
+// whose primal values in the current basic solution (RowPrim and
+// ColPrim) violate their bounds by more than 1e-7 relative to the
+// bound (the default bound tolerance of the simplex solver, see
+// Smcp). GLPK does not report the phases of the simplex method, but
+// the primal simplex needs phase I exactly when the starting basis is
+// primal infeasible, i.e. when the count is nonzero for it. To get the
+// basic solution of the starting basis solve the problem with
+// Prob.Simplex() with the iteration limit set to 0 (see
+// Smcp.SetItLim), which returns glpk.EITLIM after computing it. After
+// a successful solve the count is 0 if PrimStat is FEAS and otherwise
+// shows how far from feasibility the solver got.
+func (p *Prob) PrimalInfeasibilityCount() int {
+	const eps = 1e-7
+	count := 0
+	for i := 1; i <= p.NumRows(); i++ {
+		if !inBnds(p.RowPrim(i), p.RowType(i), p.RowLB(i), p.RowUB(i), eps) {
+			count++
+		}
+	}
+	for j := 1; j <= p.NumCols(); j++ {
+		if !inBnds(p.ColPrim(j), p.ColType(j), p.ColLB(j), p.ColUB(j), eps) {
+			count++
+		}
+	}
+	return count
+}
+
+// inBnds checks whether v satisfies bounds of type typ (lb and ub as
+// returned e.g. by Prob.ColLB() and Prob.ColUB()) with the relative
+// tolerance eps.
+func inBnds(v float64, typ BndsType, lb, ub, eps float64) bool {
+	if typ == LO || typ == DB || typ == FX {
+		if v < lb-eps*math.Max(1, math.Abs(lb)) {
+			return false
+		}
+	}
+	if typ == UP || typ == DB || typ == FX {
+		if v > ub+eps*math.Max(1, math.Abs(ub)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ObjContributions returns contributions of the columns to the
+// objective function value: contrib[j] = ObjCoef(j) * ColValue(j) for
+// j=1..n and contrib[0] is the constant term ObjCoef(0). Their sum is
+// the objective function value of the solution (ObjVal for the basic
+// solution or MipObjVal for the MIP solution, see ColValue).
+func (p *Prob) ObjContributions() []float64 {
+	contrib := p.ObjCoefs()
+	for j := 1; j < len(contrib); j++ {
+		contrib[j] *= p.ColValue(j)
+	}
+	return contrib
+}
+
+// WriteSolutionCSV writes the values of the columns in CSV format
+// with the header row "name,value,reduced_cost" followed by one record
+// per column. Values are taken from the MIP solution if the problem
+// has integer columns and a MIP solution was found, and from the
+// basic solution otherwise (see ColValue). Reduced costs (see
+// ColDual) are written only for the basic solution, for the MIP
+// solution the last field is empty.
+func (p *Prob) WriteSolutionCSV(w io.Writer) error {
+	if p.p.p == nil {
+		panic("Prob method called on a deleted problem")
+	}
+	mip := false
+	if p.NumInt() > 0 {
+		st := p.MipStatus()
+		mip = st == OPT || st == FEAS
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"name", "value", "reduced_cost"}); err != nil {
+		return err
+	}
+	for j := 1; j <= p.NumCols(); j++ {
+		var rec [3]string
+		rec[0] = p.ColName(j)
+		if mip {
+			rec[1] = strconv.FormatFloat(p.MipColVal(j), 'g', -1, 64)
+		} else {
+			rec[1] = strconv.FormatFloat(p.ColPrim(j), 'g', -1, 64)
+			rec[2] = strconv.FormatFloat(p.ColDual(j), 'g', -1, 64)
+		}
+		if err := cw.Write(rec[:]); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// SolutionsClose checks whether solutions a and b (e.g. values of
+// columns as returned by Prob.MipSolutions()) are equal within
+// tolerance eps: a[j] and b[j] are considered equal if |a[j] - b[j]|
+// <= eps * max(1, |a[j]|, |b[j]|), i.e. eps is an absolute tolerance
+// for small values and a relative tolerance for large ones. As
+// elsewhere in the glpk package the solutions are 1-based: a[0] and
+// b[0] are ignored. Solutions of different lengths are not close.
+func SolutionsClose(a, b []float64, eps float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for j := 1; j < len(a); j++ {
+		scale := math.Max(1, math.Max(math.Abs(a[j]), math.Abs(b[j])))
+		if !(math.Abs(a[j]-b[j]) <= eps*scale) {
+			return false
+		}
+	}
+	return true
+}
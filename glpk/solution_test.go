@@ -0,0 +1,160 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func CheckSolutionCSV(t *testing.T, lp *Prob, mip bool) {
+	var buf bytes.Buffer
+	if err := lp.WriteSolutionCSV(&buf); err != nil {
+		t.Fatalf("WriteSolutionCSV error: %v", err)
+	}
+	recs, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading CSV: %v", err)
+	}
+	if len(recs) != lp.NumCols()+1 {
+		t.Fatalf("expected %d records but got %d", lp.NumCols()+1, len(recs))
+	}
+	if recs[0][0] != "name" || recs[0][1] != "value" || recs[0][2] != "reduced_cost" {
+		t.Errorf("unexpected header %v", recs[0])
+	}
+	for j := 1; j <= lp.NumCols(); j++ {
+		rec := recs[j]
+		if rec[0] != lp.ColName(j) {
+			t.Errorf("expected name %q but got %q", lp.ColName(j), rec[0])
+		}
+		v, err := strconv.ParseFloat(rec[1], 64)
+		if err != nil {
+			t.Errorf("error parsing value: %v", err)
+		}
+		CheckClose(t, v, lp.ColValue(j))
+		if mip != (rec[2] == "") {
+			t.Errorf("unexpected reduced cost field %q", rec[2])
+		}
+	}
+}
+
+func TestWriteSolutionCSV(t *testing.T) {
+	lp := PrepareTestExample(t)
+	CheckSimplexSolution(t, lp)
+	CheckSolutionCSV(t, lp, false)
+	lp.Delete()
+
+	lp = PrepareMipTestExample(t)
+	defer lp.Delete()
+	if err := lp.Intopt(NewIocp(WithPresolve(true), WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	CheckSolutionCSV(t, lp, true)
+}
+
+func TestSolutionsClose(t *testing.T) {
+	a := []float64{100, 1, 2000, 0}
+	tests := []struct {
+		b    []float64
+		eps  float64
+		want bool
+	}{
+		{[]float64{-5, 1, 2000, 0}, 0, true}, // element 0 is ignored
+		{[]float64{0, 1 + 1e-9, 2000, 1e-9}, 1e-8, true},
+		{[]float64{0, 1 + 1e-7, 2000, 0}, 1e-8, false},
+		{[]float64{0, 1, 2000 + 1e-6, 0}, 1e-8, true}, // relative
+		{[]float64{0, 1, 2000}, 1e-8, false},
+		{[]float64{0, 1, 2000, math.NaN()}, 1e-8, false},
+	}
+	for _, test := range tests {
+		if got := SolutionsClose(a, test.b, test.eps); got != test.want {
+			t.Errorf("SolutionsClose(%v, %v, %g) = %v, expected %v", a, test.b, test.eps, got, test.want)
+		}
+	}
+}
+
+func TestObjContributions(t *testing.T) {
+	lp := PrepareTestExample(t)
+	lp.SetObjCoef(0, 5)
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	contrib := lp.ObjContributions()
+	expected := []float64{5, 1000.0 / 3, 400.0 / 3, 0}
+	if len(contrib) != len(expected) {
+		t.Fatalf("expected %d contributions but got %d", len(expected), len(contrib))
+	}
+	sum := 0.0
+	for j := range expected {
+		CheckClose(t, contrib[j], expected[j])
+		sum += contrib[j]
+	}
+	CheckClose(t, sum, lp.ObjVal())
+	lp.Delete()
+
+	lp = PrepareMipTestExample(t)
+	defer lp.Delete()
+	if err := lp.Intopt(NewIocp(WithPresolve(true), WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	sum = 0
+	for _, c := range lp.ObjContributions() {
+		sum += c
+	}
+	CheckClose(t, sum, lp.MipObjVal())
+}
+
+func TestCheckFeasible(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if ok, rows := lp.CheckFeasible([]float64{0, 100.0 / 3, 200.0 / 3, 0}, 1e-9); !ok || rows != nil {
+		t.Errorf("expected the optimal solution to be feasible but got %v %v", ok, rows)
+	}
+	// violates rows p (105 > 100) and q (810 > 600)
+	if ok, rows := lp.CheckFeasible([]float64{0, 65, 40, 0}, 1e-9); ok || len(rows) != 2 || rows[0] != 1 || rows[1] != 2 {
+		t.Errorf("expected rows 1 and 2 to be violated but got %v %v", ok, rows)
+	}
+	// violates the lower bound of x1 only
+	if ok, rows := lp.CheckFeasible([]float64{0, 10, -1, 0}, 1e-9); ok || rows != nil {
+		t.Errorf("expected infeasibility without violated rows but got %v %v", ok, rows)
+	}
+}
+
+func TestPrimalInfeasibilityCount(t *testing.T) {
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	lp.SetRowBnds(1, DB, 50, 100)
+	smcp := NewSmcp(WithMsgLev(MSG_ERR))
+	smcp.SetItLim(0)
+	if err := lp.Simplex(smcp); err != EITLIM {
+		t.Fatalf("expected EITLIM but got %v", err)
+	}
+	// the standard basis (all columns at zero) violates the first row
+	if n := lp.PrimalInfeasibilityCount(); n != 1 {
+		t.Errorf("expected 1 infeasibility but got %d", n)
+	}
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ERR))); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if n := lp.PrimalInfeasibilityCount(); n != 0 {
+		t.Errorf("expected no infeasibilities but got %d", n)
+	}
+}
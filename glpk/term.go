@@ -0,0 +1,280 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// #include <glpk.h>
+// #include <pthread.h>
+import "C"
+
+var (
+	termMu       sync.Mutex // serializes solves with redirected output
+	termWriter   io.Writer  // writer for the GLPK terminal output
+	termPanicked bool
+	termPanicVal interface{}
+)
+
+// termThread is the thread holding termMu (if termHeld) used to detect
+// calls of withOutput() from a callback of a solve with redirected
+// output (e.g. Prob.WriteLP() from an Iocp callback).
+var (
+	ownerMu    sync.Mutex
+	termHeld   bool
+	termThread C.pthread_t
+)
+
+// holdsTerm returns whether termMu is held by the current thread. As
+// the holder is locked to its thread no other goroutine may run on it.
+func holdsTerm() bool {
+	ownerMu.Lock()
+	defer ownerMu.Unlock()
+	return termHeld && C.pthread_equal(termThread, C.pthread_self()) != 0
+}
+
+func setTermOwner(held bool) {
+	ownerMu.Lock()
+	termHeld = held
+	termThread = C.pthread_self()
+	ownerMu.Unlock()
+}
+
+// LogHandler handles lines of the GLPK terminal output (see
+// SetLogHandler).
+type LogHandler interface {
+	// Handle is called for each line of the output (without the
+	// trailing newline). level is glpk.MSG_ERR for warning and error
+	// messages and glpk.MSG_ON for other messages.
+	Handle(level MsgLev, msg string)
+}
+
+var (
+	logMu      sync.Mutex
+	logHandler LogHandler
+)
+
+// SetLogHandler sets the handler of the GLPK terminal output of
+// Prob.Simplex(), Prob.Exact(), and Prob.Intopt() for solves with no
+// output writer set (see Smcp.SetOutput and Iocp.SetOutput). A nil h
+// (the default) means that the output goes to stdout. The output is
+// split into lines and the level of each line is guessed from its
+// contents (GLPK does not report it): lines starting with "glp_" (the
+// name of the GLPK routine reporting a problem) or containing
+// "error" or "warning" (in any case) have level glpk.MSG_ERR. As with
+// SetOutput solves with the output handled are serialized. The output
+// of the reading and writing functions (e.g. Prob.ReadLP(),
+// Prob.WriteMPS(), and Graph.ReadMincostDIMACS()) bypasses the handler
+// and goes to stdout unless SetFileErrorDetails is enabled.
+func SetLogHandler(h LogHandler) {
+	logMu.Lock()
+	logHandler = h
+	logMu.Unlock()
+}
+
+// logWriter splits the output into lines passed to a LogHandler.
+type logWriter struct {
+	h   LogHandler
+	buf []byte
+}
+
+func (w *logWriter) Write(b []byte) (int, error) {
+	w.buf = append(w.buf, b...)
+	for {
+		k := bytes.IndexByte(w.buf, '\n')
+		if k < 0 {
+			break
+		}
+		w.handle(string(w.buf[:k]))
+		w.buf = w.buf[k+1:]
+	}
+	return len(b), nil
+}
+
+// flush passes the incomplete last line (if any) to the handler.
+func (w *logWriter) flush() {
+	if len(w.buf) > 0 {
+		w.handle(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *logWriter) handle(line string) {
+	w.h.Handle(logLevel(line), line)
+}
+
+// logLevel guesses the message level of a line of the GLPK output.
+func logLevel(line string) MsgLev {
+	l := strings.ToLower(line)
+	if strings.HasPrefix(l, "glp_") || strings.Contains(l, "error") || strings.Contains(l, "warning") {
+		return MSG_ERR
+	}
+	return MSG_ON
+}
+
+// withOutput calls f with the GLPK terminal output redirected to w
+// (or to the LogHandler, if any, if w is nil). As the term hook of
+// GLPK is global (or per thread in newer GLPK versions) calls with
+// redirected output are serialized and f is run with the goroutine
+// locked to its thread. After f returns the default output (to
+// stdout) is restored. If called from f (e.g. from a solver callback)
+// the output of the inner call goes to w (or to the outer writer if w
+// is nil) and the outer writer is restored afterwards.
+func withOutput(w io.Writer, f func()) {
+	if holdsTerm() {
+		if w != nil {
+			outer := termWriter
+			termWriter = w
+			defer func() { termWriter = outer }()
+		}
+		f()
+		return
+	}
+	if w == nil {
+		logMu.Lock()
+		h := logHandler
+		logMu.Unlock()
+		if h == nil {
+			f()
+			return
+		}
+		lw := &logWriter{h: h}
+		defer lw.flush()
+		w = lw
+	}
+	termMu.Lock()
+	defer termMu.Unlock()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	setTermOwner(true)
+	defer setTermOwner(false)
+	termWriter = w
+	termPanicked = false
+	setTermHook(true)
+	defer func() {
+		setTermHook(false)
+		termWriter = nil
+	}()
+	f()
+	if termPanicked {
+		panic(termPanicVal)
+	}
+}
+
+//export goTermHook
+func goTermHook(info unsafe.Pointer, s *C.char) C.int {
+	// a panic must not unwind through the C code of GLPK so it is
+	// recovered here and repeated after the solver returns
+	defer func() {
+		if r := recover(); r != nil && !termPanicked {
+			termPanicked = true
+			termPanicVal = r
+		}
+	}()
+	if !termPanicked {
+		termWriter.Write([]byte(C.GoString(s)))
+	}
+	return 1 // suppress the default output
+}
+
+var fileDetails bool // guarded by logMu
+
+// SetFileErrorDetails enables or disables (the default) adding the
+// last line of the GLPK terminal output (which usually is the
+// diagnostic message, e.g. with the line number of a syntax error) to
+// the Message of a PathError returned by reading and writing
+// functions. As this requires installing the GLPK term hook it is
+// opt-in: with details enabled reading and writing is serialized with
+// the solves with redirected output (see Smcp.SetOutput). The output
+// itself still goes to stdout (or to the LogHandler, see
+// SetLogHandler), or to the output of the solve if called from a
+// solver callback (e.g. the one set with Iocp.SetCallback).
+func SetFileErrorDetails(on bool) {
+	logMu.Lock()
+	fileDetails = on
+	logMu.Unlock()
+}
+
+// teeWriter writes to w and keeps a copy of the output.
+type teeWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	return w.w.Write(b)
+}
+
+// lastLine returns the last nonempty line of the output.
+func (w *teeWriter) lastLine() string {
+	lines := strings.Split(w.buf.String(), "\n")
+	for k := len(lines) - 1; k >= 0; k-- {
+		if line := strings.TrimSpace(lines[k]); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// fileOp calls f (a GLPK reading or writing routine which returns
+// nonzero on error) and returns a PathError if it fails. msg is
+// followed by the last line of the GLPK output if enabled by
+// SetFileErrorDetails.
+func fileOp(op, filename, msg string, f func() C.int) error {
+	logMu.Lock()
+	details, h := fileDetails, logHandler
+	logMu.Unlock()
+	var ret C.int
+	if !details {
+		ret = f()
+	} else {
+		w := &teeWriter{w: os.Stdout}
+		if holdsTerm() {
+			// called from a solver callback: tee into its output
+			w.w = termWriter
+		} else if h != nil {
+			lw := &logWriter{h: h}
+			defer lw.flush()
+			w.w = lw
+		}
+		withOutput(w, func() { ret = f() })
+		if line := w.lastLine(); ret != 0 && line != "" {
+			msg += ": " + line
+		}
+	}
+	if ret != 0 {
+		return &PathError{op, filename, msg}
+	}
+	return nil
+}
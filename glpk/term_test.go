@@ -0,0 +1,143 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testLogHandler struct {
+	msgs   []string
+	levels []MsgLev
+}
+
+func (h *testLogHandler) Handle(level MsgLev, msg string) {
+	h.msgs = append(h.msgs, msg)
+	h.levels = append(h.levels, level)
+}
+
+func TestLogHandler(t *testing.T) {
+	h := &testLogHandler{}
+	SetLogHandler(h)
+	defer SetLogHandler(nil)
+	lp := PrepareTestExample(t)
+	defer lp.Delete()
+	if err := lp.Simplex(NewSmcp(WithMsgLev(MSG_ALL))); err != nil {
+		t.Errorf("Simplex error: %v", err)
+	}
+	CheckSolution(t, lp)
+	if len(h.msgs) == 0 {
+		t.Fatal("expected simplex output to be handled")
+	}
+	for k, msg := range h.msgs {
+		if h.levels[k] != MSG_ON {
+			t.Errorf("expected level %v for %q but got %v", MSG_ON, msg, h.levels[k])
+		}
+	}
+}
+
+func TestLogWriter(t *testing.T) {
+	h := &testLogHandler{}
+	w := &logWriter{h: h}
+	w.Write([]byte("abc"))
+	w.Write([]byte("def\nglp_simplex: unable to factorize the basis matrix\nWarning: x"))
+	w.Write([]byte("\nlast"))
+	w.flush()
+	expected := []string{"abcdef", "glp_simplex: unable to factorize the basis matrix", "Warning: x", "last"}
+	levels := []MsgLev{MSG_ON, MSG_ERR, MSG_ERR, MSG_ON}
+	if len(h.msgs) != len(expected) {
+		t.Fatalf("expected %q but got %q", expected, h.msgs)
+	}
+	for k := range expected {
+		if h.msgs[k] != expected[k] || h.levels[k] != levels[k] {
+			t.Errorf("expected %q (%v) but got %q (%v)", expected[k], levels[k], h.msgs[k], h.levels[k])
+		}
+	}
+}
+
+func TestFileErrorDetails(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "bad.lp")
+	if err := ioutil.WriteFile(name, []byte("maximize\n obj: x +\nsubject to\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	lp := New()
+	defer lp.Delete()
+	if err, ok := lp.ReadLP(nil, name).(*PathError); !ok || err.Message != "CPLEX LP reading error" {
+		t.Errorf("expected PathError without details but got %v", err)
+	}
+	SetFileErrorDetails(true)
+	defer SetFileErrorDetails(false)
+	h := &testLogHandler{}
+	SetLogHandler(h)
+	defer SetLogHandler(nil)
+	err = lp.ReadLP(nil, name)
+	perr, ok := err.(*PathError)
+	if !ok {
+		t.Fatalf("expected PathError but got %v", err)
+	}
+	if prefix := "CPLEX LP reading error: "; !strings.HasPrefix(perr.Message, prefix) || len(perr.Message) == len(prefix) {
+		t.Errorf("expected details of the error but got %q", perr.Message)
+	}
+	if len(h.msgs) == 0 {
+		t.Error("expected the output to be still handled")
+	}
+}
+
+func TestFileErrorDetailsInCallback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glpk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	SetFileErrorDetails(true)
+	defer SetFileErrorDetails(false)
+	var buf bytes.Buffer
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	iocp.SetOutput(&buf)
+	written := false
+	iocp.SetCallback(func(tree *Tree) {
+		if !written {
+			written = true
+			if err := lp.WriteLP(nil, filepath.Join(dir, "p.lp")); err != nil {
+				t.Errorf("WriteLP error: %v", err)
+			}
+		}
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+	if !written {
+		t.Fatal("expected the callback to be called")
+	}
+	if !strings.Contains(buf.String(), "Writing problem data") {
+		t.Errorf("expected the output of WriteLP in the output of Intopt but got %q", buf.String())
+	}
+}
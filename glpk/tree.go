@@ -0,0 +1,234 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Some comments/strings are taken or adapted from GLPK and thus are
+// subject to the following copyright:
+//
+// Copyright (C) 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008,
+// 2009, 2010, 2011, 2013, 2014 Andrew Makhorin, Department for Applied
+// Informatics, Moscow Aviation Institute, Moscow, Russia. All rights
+// reserved. E-mail: <mao@gnu.org>.
+//
+// Pacakge glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// #include <glpk.h>
+import "C"
+
+// Reason specifies the reason for calling the branch-and-cut callback
+// (see Iocp.SetCallback()).
+type Reason int
+
+// Allowed values of type Reason (reason for calling the callback).
+const (
+	IROWGEN = Reason(C.GLP_IROWGEN) // request for row generation
+	IBINGO  = Reason(C.GLP_IBINGO)  // better integer solution found
+	IHEUR   = Reason(C.GLP_IHEUR)   // request for heuristic solution
+	ICUTGEN = Reason(C.GLP_ICUTGEN) // request for cut generation
+	IBRANCH = Reason(C.GLP_IBRANCH) // request for branching
+	ISELECT = Reason(C.GLP_ISELECT) // request for subproblem selection
+	IPREPRO = Reason(C.GLP_IPREPRO) // request for preprocessing
+)
+
+// BranchDir specifies which branch should be selected next when
+// branching (see Tree.BranchUpon()).
+type BranchDir int
+
+// Allowed values of type BranchDir (branch to select next).
+const (
+	DN_BRNCH = BranchDir(C.GLP_DN_BRNCH) // select down-branch
+	UP_BRNCH = BranchDir(C.GLP_UP_BRNCH) // select up-branch
+	NO_BRNCH = BranchDir(C.GLP_NO_BRNCH) // use general selection technique
+)
+
+// Tree represents the branch-and-cut search tree passed to the
+// callback set with Iocp.SetCallback(). It is valid only during the
+// call to the callback.
+//
+// Note that with the MIP presolver enabled the search is done on the
+// presolved problem so row and column numbers used by Tree methods
+// refer to that problem. Disable the presolver if the callback uses
+// row or column numbers of the original problem.
+type Tree struct {
+	t *C.glp_tree
+}
+
+// Reason returns the reason for calling the callback.
+func (t *Tree) Reason() Reason {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+	return Reason(C.glp_ios_reason(t.t))
+}
+
+// ColPrim returns primal value of the j-th column for the LP
+// relaxation of the current subproblem.
+func (t *Tree) ColPrim(j int) float64 {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+	return float64(C.glp_get_col_prim(C.glp_ios_get_prob(t.t), C.int(j)))
+}
+
+// Terminate terminates the search. Prob.Intopt() then returns
+// glpk.ESTOP.
+func (t *Tree) Terminate() {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+	C.glp_ios_terminate(t.t)
+}
+
+// AddLazyRow adds constraint sum val[k]*x[ind[k]] (for k = 1..n) typ
+// rhs, where typ is glpk.LO (">= rhs"), glpk.UP ("<= rhs"), or
+// glpk.FX ("= rhs"). ind[1]..ind[n] are column numbers and
+// val[1]..val[n] are the coefficients (ind[0] and val[0] are ignored).
+//
+// It may be called only if Reason is glpk.IROWGEN, in which case the
+// row is added to the current subproblem as a lazy constraint (which
+// is a part of all the subproblems derived from it), or
+// glpk.ICUTGEN, in which case the row is added to the cut pool as a
+// cutting plane. It panics for any other reason.
+func (t *Tree) AddLazyRow(ind []int32, val []float64, typ BndsType, rhs float64) {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+	if len(ind) != len(val) {
+		panic("len(ind) and len(val) should be equal")
+	}
+	if typ != LO && typ != UP && typ != FX {
+		panic("AddLazyRow requires glpk.LO, glpk.UP, or glpk.FX bounds type")
+	}
+	indH := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
+	valH := (*reflect.SliceHeader)(unsafe.Pointer(&val))
+	indC := (*C.int)(unsafe.Pointer(indH.Data))
+	valC := (*C.double)(unsafe.Pointer(valH.Data))
+	switch Reason(C.glp_ios_reason(t.t)) {
+	case IROWGEN:
+		prob := C.glp_ios_get_prob(t.t)
+		i := C.glp_add_rows(prob, 1)
+		C.glp_set_mat_row(prob, i, C.int(len(ind)-1), indC, valC)
+		C.glp_set_row_bnds(prob, i, C.int(typ), C.double(rhs), C.double(rhs))
+	case ICUTGEN:
+		C.glp_ios_add_row(t.t, nil, 0, 0, C.int(len(ind)-1), indC, valC, C.int(typ), C.double(rhs))
+	default:
+		panic("AddLazyRow called for a reason other than glpk.IROWGEN or glpk.ICUTGEN")
+	}
+}
+
+// MipGap returns the current relative MIP gap, i.e. |best_mip -
+// best_bnd| / (|best_mip| + epsilon), where best_mip is the objective
+// value of the best integer feasible solution found so far and
+// best_bnd is the best bound over all active subproblems. It returns
+// +Inf if no integer feasible solution has been found yet.
+func (t *Tree) MipGap() float64 {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+	prob := C.glp_ios_get_prob(t.t)
+	if st := SolStat(C.glp_mip_status(prob)); st != OPT && st != FEAS {
+		return math.Inf(1)
+	}
+	return float64(C.glp_ios_mip_gap(t.t))
+}
+
+// AbsMipGap returns the current absolute MIP gap, i.e. |best_mip -
+// best_bnd| (see MipGap). It returns +Inf if no integer feasible
+// solution has been found yet.
+func (t *Tree) AbsMipGap() float64 {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+	prob := C.glp_ios_get_prob(t.t)
+	if st := SolStat(C.glp_mip_status(prob)); st != OPT && st != FEAS {
+		return math.Inf(1)
+	}
+	node := C.glp_ios_best_node(t.t)
+	if node == 0 {
+		return 0
+	}
+	return math.Abs(float64(C.glp_mip_obj_val(prob) - C.glp_ios_node_bound(t.t, node)))
+}
+
+// CanBranch checks whether the j-th column can be branched upon, i.e.
+// it is an integer column with a fractional value in the LP relaxation
+// of the current subproblem. It may be called only if Reason is
+// glpk.IBRANCH and panics otherwise.
+func (t *Tree) CanBranch(j int) bool {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+	if Reason(C.glp_ios_reason(t.t)) != IBRANCH {
+		panic("CanBranch called for a reason other than glpk.IBRANCH")
+	}
+	return C.glp_ios_can_branch(t.t, C.int(j)) != 0
+}
+
+// BranchUpon chooses the j-th column to branch upon in the current
+// subproblem. dir specifies which of the two new subproblems should be
+// selected to continue the search: glpk.DN_BRNCH (the one with
+// x[j] <= floor(x[j])), glpk.UP_BRNCH (the one with x[j] >=
+// ceil(x[j])), or glpk.NO_BRNCH (let the backtracking technique
+// choose). If the callback does not call BranchUpon GLPK selects the
+// column using its branching technique.
+//
+// It may be called only if Reason is glpk.IBRANCH and only for a
+// column for which CanBranch returns true, otherwise it panics.
+func (t *Tree) BranchUpon(j int, dir BranchDir) {
+	if !t.CanBranch(j) {
+		panic("BranchUpon called for a column which cannot be branched upon")
+	}
+	C.glp_ios_branch_upon(t.t, C.int(j), C.int(dir))
+}
+
+// NodeCount returns the total number of subproblems (nodes of the
+// branch-and-bound tree) generated so far, including the ones already
+// solved and removed from the tree (as reported by
+// glp_ios_tree_size).
+func (t *Tree) NodeCount() int {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+	var aCnt, nCnt, tCnt C.int
+	C.glp_ios_tree_size(t.t, &aCnt, &nCnt, &tCnt)
+	return int(tCnt)
+}
+
+// HeurSol provides an integer feasible solution found by a heuristic
+// to the solver. x[1]..x[n] are values of the columns (x[0] is
+// ignored). It returns true if the solution was accepted as the new
+// incumbent and false if it was rejected (e.g. it is not better than
+// the incumbent). GLPK checks integrality of the solution but not the
+// bounds nor the rows, so the solution must be feasible. It may be
+// called only if Reason is glpk.IHEUR and panics otherwise.
+func (t *Tree) HeurSol(x []float64) bool {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+	if Reason(C.glp_ios_reason(t.t)) != IHEUR {
+		panic("HeurSol called for a reason other than glpk.IHEUR")
+	}
+	if len(x) != int(C.glp_get_num_cols(C.glp_ios_get_prob(t.t)))+1 {
+		panic("len(x) should be equal to the number of columns plus one")
+	}
+	return C.glp_ios_heur_sol(t.t, (*C.double)(unsafe.Pointer(&x[0]))) == 0
+}
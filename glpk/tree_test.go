@@ -0,0 +1,259 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"math"
+	"testing"
+)
+
+// PrepareLazyTestExample prepares problem: maximize x + y subject to
+// x + 2 y <= 100, 0 <= x, y <= 10, x and y integer. With a lazy
+// constraint x + y <= 5 the optimal value is 5.
+func PrepareLazyTestExample(t *testing.T) *Prob {
+	lp := New()
+	lp.SetObjDir(MAX)
+	lp.AddRows(1)
+	lp.SetRowBnds(1, UP, 0, 100)
+	lp.AddCols(2)
+	for j := 1; j <= 2; j++ {
+		lp.SetColBnds(j, DB, 0, 10)
+		lp.SetColKind(j, IV)
+		lp.SetObjCoef(j, 1)
+	}
+	lp.SetMatRow(1, []int32{0, 1, 2}, []float64{0, 1, 2})
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	return lp
+}
+
+func TestTreeAddLazyRow(t *testing.T) {
+	lp := PrepareLazyTestExample(t)
+	defer lp.Delete()
+	iocp := NewIocp()
+	iocp.SetMsgLev(MSG_ERR)
+	added := 0
+	iocp.SetCallback(func(tree *Tree) {
+		if tree.Reason() != IROWGEN {
+			return
+		}
+		if tree.ColPrim(1)+tree.ColPrim(2) > 5+1e-9 {
+			tree.AddLazyRow([]int32{0, 1, 2}, []float64{0, 1, 1}, UP, 5)
+			added++
+		}
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	if added == 0 {
+		t.Error("expected the lazy constraint to be added")
+	}
+	CheckClose(t, lp.MipObjVal(), 5)
+}
+
+func TestTreeAddLazyRowReason(t *testing.T) {
+	lp := PrepareLazyTestExample(t)
+	defer lp.Delete()
+	iocp := NewIocp()
+	iocp.SetMsgLev(MSG_ERR)
+	panicked := false
+	iocp.SetCallback(func(tree *Tree) {
+		if tree.Reason() != IBINGO {
+			return
+		}
+		defer func() {
+			if recover() != nil {
+				panicked = true
+			}
+		}()
+		tree.AddLazyRow([]int32{0, 1}, []float64{0, 1}, UP, 1)
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	if !panicked {
+		t.Error("expected AddLazyRow to panic for glpk.IBINGO")
+	}
+	CheckClose(t, lp.MipObjVal(), 20)
+}
+
+func TestMipGap(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	if gap := lp.FinalMipGap(); !math.IsInf(gap, 1) {
+		t.Errorf("expected +Inf gap before solving but got %g", gap)
+	}
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	iocp.SetMsgLev(MSG_ERR)
+	iocp.SetCallback(func(tree *Tree) {
+		rel, abs := tree.MipGap(), tree.AbsMipGap()
+		if rel < 0 || abs < 0 {
+			t.Errorf("expected nonnegative gaps but got %g and %g", rel, abs)
+		}
+		if math.IsInf(rel, 1) != math.IsInf(abs, 1) {
+			t.Errorf("expected both gaps to be infinite or finite but got %g and %g", rel, abs)
+		}
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	CheckMipSolution(t, lp)
+	CheckClose(t, lp.FinalMipGap(), 0)
+
+	// without the callback the bound is known only for the optimum
+	iocp.SetCallback(nil)
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	CheckClose(t, lp.FinalMipGap(), 0)
+	if n := lp.MipNodeCount(); n != 0 {
+		t.Errorf("expected no recorded nodes without the callback but got %d", n)
+	}
+}
+
+func TestTreeBranchUpon(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	iocp := NewIocp()
+	iocp.SetMsgLev(MSG_ERR)
+	branched := 0
+	iocp.SetCallback(func(tree *Tree) {
+		if tree.Reason() != IBRANCH {
+			return
+		}
+		for j := 1; j <= lp.NumCols(); j++ {
+			if tree.CanBranch(j) {
+				tree.BranchUpon(j, UP_BRNCH)
+				branched++
+				return
+			}
+		}
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	if branched == 0 {
+		t.Error("expected the callback to branch")
+	}
+	CheckMipSolution(t, lp)
+}
+
+func TestNodeCount(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	iocp := NewIocp(WithPresolve(true), WithMsgLev(MSG_ERR))
+	last := 0
+	iocp.SetCallback(func(tree *Tree) {
+		n := tree.NodeCount()
+		if n < last {
+			t.Errorf("node count decreased from %d to %d", last, n)
+		}
+		last = n
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	CheckMipSolution(t, lp)
+	if n := lp.MipNodeCount(); n != last {
+		t.Errorf("expected %d nodes but got %d", last, n)
+	}
+}
+
+func TestInitialSolution(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	iocp := NewIocp(WithMsgLev(MSG_ERR), WithInitialSolution([]float64{0, 14, 7, 7, 2.5}))
+	if err := lp.Intopt(iocp); err != ErrInvalidSolution {
+		t.Fatalf("expected ErrInvalidSolution but got %v", err)
+	}
+	iocp.SetInitialSolution([]float64{0, 14, 7, 7, 2})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	CheckMipSolution(t, lp)
+}
+
+func TestTreeHeurSol(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	iocp := NewIocp(WithMsgLev(MSG_ERR))
+	called := false
+	iocp.SetCallback(func(tree *Tree) {
+		if tree.Reason() == IHEUR && !called {
+			called = true
+			tree.HeurSol([]float64{0, 14, 7, 7, 2})
+		}
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	if !called {
+		t.Error("expected the callback to be called with glpk.IHEUR")
+	}
+	CheckMipSolution(t, lp)
+}
+
+func TestSetHeuristic(t *testing.T) {
+	lp := PrepareMipTestExample(t)
+	defer lp.Delete()
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	calls := 0
+	iocp := NewIocp(WithMsgLev(MSG_ERR), WithHeuristic(func(relax []float64) ([]float64, bool) {
+		calls++
+		if len(relax) != 5 {
+			t.Errorf("expected relaxation of length 5 but got %d", len(relax))
+		}
+		switch calls {
+		case 1:
+			return []float64{0, 14, 7}, true // wrong length
+		case 2:
+			return []float64{0, 14, 7, 7, 2.5}, true // not integer
+		}
+		return []float64{0, 14, 7, 7, 2}, true
+	}))
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Mip error: %v", err)
+	}
+	if calls == 0 {
+		t.Error("expected the heuristic to be called")
+	}
+	CheckMipSolution(t, lp)
+}
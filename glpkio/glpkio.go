@@ -0,0 +1,104 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+// Package glpkio reads and writes model data for the glpk package in
+// simple text formats, e.g. the constraint matrix as a CSV file (as
+// exported from a spreadsheet), which decouples data ingestion from
+// building the model.
+package glpkio
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LoadTriplets reads the nonzero elements of a constraint matrix from
+// CSV records "i,j,value" (row number, column number, and the value of
+// the element, with rows and columns numbered from 1). An optional
+// first record which is not numeric (e.g. "i,j,value") is skipped as
+// a header. The elements are returned in the convention of
+// glpk.Prob.LoadMatrix(): matrix[ia[k], ja[k]] = ar[k] for
+// k=1..len(ia)-1, while ia[0], ja[0], and ar[0] are zero (and
+// ignored), so the result may be passed to it directly.
+func LoadTriplets(r io.Reader) (ia, ja []int32, ar []float64, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+	ia, ja, ar = []int32{0}, []int32{0}, []float64{0}
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		i, erri := strconv.ParseInt(strings.TrimSpace(rec[0]), 10, 32)
+		j, errj := strconv.ParseInt(strings.TrimSpace(rec[1]), 10, 32)
+		v, errv := strconv.ParseFloat(strings.TrimSpace(rec[2]), 64)
+		if line == 1 && erri != nil && errj != nil && errv != nil {
+			continue // header
+		}
+		switch {
+		case erri != nil || i < 1:
+			return nil, nil, nil, lineError(line, "invalid row number "+strconv.Quote(rec[0]))
+		case errj != nil || j < 1:
+			return nil, nil, nil, lineError(line, "invalid column number "+strconv.Quote(rec[1]))
+		case errv != nil:
+			return nil, nil, nil, lineError(line, "invalid value "+strconv.Quote(rec[2]))
+		}
+		ia = append(ia, int32(i))
+		ja = append(ja, int32(j))
+		ar = append(ar, v)
+	}
+	return ia, ja, ar, nil
+}
+
+// WriteTriplets writes the elements matrix[ia[k], ja[k]] = ar[k] for
+// k=1..len(ia)-1 (ia[0], ja[0], and ar[0] are ignored, as in
+// glpk.Prob.LoadMatrix()) as CSV records "i,j,value" preceded by the
+// header "i,j,value", in the format read by LoadTriplets. Requires
+// len(ia) = len(ja) = len(ar).
+func WriteTriplets(w io.Writer, ia, ja []int32, ar []float64) error {
+	if len(ia) != len(ja) || len(ia) != len(ar) {
+		panic("len(ia), len(ja), and len(ar) should be equal")
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"i", "j", "value"}); err != nil {
+		return err
+	}
+	for k := 1; k < len(ia); k++ {
+		rec := []string{
+			strconv.Itoa(int(ia[k])),
+			strconv.Itoa(int(ja[k])),
+			strconv.FormatFloat(ar[k], 'g', -1, 64),
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// lineError returns an error for the given record (line) of the input.
+func lineError(line int, msg string) error {
+	return errors.New("line " + strconv.Itoa(line) + ": " + msg)
+}
@@ -0,0 +1,79 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpkio
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadTriplets(t *testing.T) {
+	ia, ja, ar, err := LoadTriplets(strings.NewReader("i,j,value\n1,1,2.5\n2, 3, -1\n1,2,1e3\n"))
+	if err != nil {
+		t.Fatalf("LoadTriplets error: %v", err)
+	}
+	if expected := []int32{0, 1, 2, 1}; !reflect.DeepEqual(ia, expected) {
+		t.Errorf("expected ia = %v but got %v", expected, ia)
+	}
+	if expected := []int32{0, 1, 3, 2}; !reflect.DeepEqual(ja, expected) {
+		t.Errorf("expected ja = %v but got %v", expected, ja)
+	}
+	if expected := []float64{0, 2.5, -1, 1000}; !reflect.DeepEqual(ar, expected) {
+		t.Errorf("expected ar = %v but got %v", expected, ar)
+	}
+
+	// no header
+	if ia, _, _, err = LoadTriplets(strings.NewReader("3,4,5\n")); err != nil {
+		t.Fatalf("LoadTriplets error: %v", err)
+	} else if len(ia) != 2 || ia[1] != 3 {
+		t.Errorf("expected ia = [0 3] but got %v", ia)
+	}
+
+	for _, data := range []string{
+		"1,1,2\n0,1,2\n",
+		"1,x,2\n",
+		"1,1,abc\n",
+		"1,1\n",
+	} {
+		if _, _, _, err := LoadTriplets(strings.NewReader(data)); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+}
+
+func TestWriteTriplets(t *testing.T) {
+	ia := []int32{0, 1, 2, 1}
+	ja := []int32{0, 1, 3, 2}
+	ar := []float64{0, 2.5, -1, 1000}
+	var buf bytes.Buffer
+	if err := WriteTriplets(&buf, ia, ja, ar); err != nil {
+		t.Fatalf("WriteTriplets error: %v", err)
+	}
+	if s, expected := buf.String(), "i,j,value\n1,1,2.5\n2,3,-1\n1,2,1000\n"; s != expected {
+		t.Errorf("expected %q but got %q", expected, s)
+	}
+	ia1, ja1, ar1, err := LoadTriplets(&buf)
+	if err != nil {
+		t.Fatalf("LoadTriplets error: %v", err)
+	}
+	if !reflect.DeepEqual(ia1, ia) || !reflect.DeepEqual(ja1, ja) || !reflect.DeepEqual(ar1, ar) {
+		t.Errorf("expected (%v, %v, %v) but got (%v, %v, %v)", ia, ja, ar, ia1, ja1, ar1)
+	}
+}